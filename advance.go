@@ -38,17 +38,16 @@ func NewAdvance(name string, interval, modificator int) *Advance {
 func (obj *Advance) GenerateAdvance() int {
 	advance := obj.Interval
 	if obj.Modificator > 0 {
-		advance += GetRandom(-obj.Modificator, obj.Modificator)
+		advance += obj.GetRandom(-obj.Modificator, obj.Modificator)
 	}
 	return advance
 }
 
 func (obj *Advance) HandleTransact(transact ITransaction) {
 	transact.DecTiсks()
-	transact.PrintInfo()
 	if transact.IsTheEnd() {
 		for _, v := range obj.GetDst() {
-			if v.AppendTransact(transact) {
+			if SendTransact(obj, v, transact) {
 				obj.tb.Remove(transact)
 				break
 			}
@@ -64,7 +63,7 @@ func (obj *Advance) HandleTransacts(wg *sync.WaitGroup) {
 	}
 	go func() {
 		defer wg.Done()
-		transacts := obj.tb.GetItems()
+		transacts := getStepItems(obj.tb)
 		for _, tr := range transacts {
 			obj.HandleTransact(tr.transact)
 		}
@@ -72,7 +71,6 @@ func (obj *Advance) HandleTransacts(wg *sync.WaitGroup) {
 }
 
 func (obj *Advance) AppendTransact(transact ITransaction) bool {
-	obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Advance")
 	transact.SetHolderName(obj.name)
 	advance := obj.GenerateAdvance()
 	obj.sum_advance += float64(advance)
@@ -87,3 +85,21 @@ func (obj *Advance) PrintReport() {
 	fmt.Printf("Average advance %.2f\n", obj.sum_advance/obj.sum_transact)
 	fmt.Println()
 }
+
+type advanceState struct {
+	SumAdvance  float64
+	SumTransact float64
+}
+
+func (obj *Advance) SaveState() ([]byte, error) {
+	return encodeState(advanceState{SumAdvance: obj.sum_advance, SumTransact: obj.sum_transact})
+}
+
+func (obj *Advance) LoadState(data []byte) error {
+	state := advanceState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	obj.sum_advance, obj.sum_transact = state.SumAdvance, state.SumTransact
+	return nil
+}
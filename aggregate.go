@@ -25,7 +25,7 @@ func NewAggregate(name string) *Aggregate {
 
 func (obj *Aggregate) SendToDst(transact ITransaction) bool {
 	for _, v := range obj.GetDst() {
-		if v.AppendTransact(transact) {
+		if SendTransact(obj, v, transact) {
 			obj.tb.Remove(transact)
 			obj.sum_transact++
 			return true
@@ -34,8 +34,18 @@ func (obj *Aggregate) SendToDst(transact ITransaction) bool {
 	return false
 }
 
+// Send fully aggregated transact to destination
+func (obj *Aggregate) sendAggregated(transact ITransaction) bool {
+	if !obj.SendToDst(transact) {
+		return false
+	}
+	for _, o := range getObservers(obj.GetPipeline()) {
+		o.OnAggregate(obj, transact)
+	}
+	return true
+}
+
 func (obj *Aggregate) HandleTransact(transact ITransaction) bool {
-	transact.PrintInfo()
 	_, parts, parent_id := transact.GetParts()
 	if parent_id == 0 {
 		return obj.SendToDst(transact)
@@ -45,25 +55,37 @@ func (obj *Aggregate) HandleTransact(transact ITransaction) bool {
 		tr := transact.Copy()
 		tr.SetID(parent_id)
 		tr.SetParts(0, parts-1, 0)
+		tr.SetHolderName(obj.name)
 		if parts-1 == 0 {
-			return obj.SendToDst(tr)
-		}
-		obj.tb.Push(tr)
-	} else {
-		// Update Advance
-		if holded_tr.transact.GetAdvanceTime() < transact.GetAdvanceTime() {
-			holded_tr.transact.SetTiсks(transact.GetAdvanceTime())
-			holded_tr.transact.SetTiсks(0)
-		}
-		_, holded_parts, _ := holded_tr.transact.GetParts()
-		if holded_parts-1 == 0 {
-			// We aggregate all parts
-			holded_tr.transact.SetParts(0, 0, 0)
-			return obj.SendToDst(holded_tr.transact)
+			if !obj.sendAggregated(tr) {
+				return false
+			}
 		} else {
-			holded_tr.transact.SetParts(0, holded_parts-1, 0)
+			obj.tb.Push(tr)
 		}
+		// Part is replaced by aggregated transact
+		transact.SetHolderName(obj.name)
+		return true
+	}
+	// Update Advance
+	if holded_tr.transact.GetAdvanceTime() < transact.GetAdvanceTime() {
+		holded_tr.transact.SetTiсks(transact.GetAdvanceTime())
+		holded_tr.transact.SetTiсks(0)
 	}
+	_, holded_parts, _ := holded_tr.transact.GetParts()
+	if holded_parts-1 == 0 {
+		// We aggregate all parts
+		holded_tr.transact.SetParts(0, 0, 0)
+		if !obj.sendAggregated(holded_tr.transact) {
+			holded_tr.transact.SetParts(0, holded_parts, 0)
+			return false
+		}
+	} else {
+		holded_tr.transact.SetParts(0, holded_parts-1, 0)
+	}
+	// Part is joined to holded transact
+	transact.SetHolderName(obj.name)
+	addCounters(obj, 0, -1)
 	return true
 }
 
@@ -73,8 +95,6 @@ func (obj *Aggregate) HandleTransacts(wg *sync.WaitGroup) {
 }
 
 func (obj *Aggregate) AppendTransact(transact ITransaction) bool {
-	obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Aggregate")
-	transact.SetHolderName(obj.name)
 	return obj.HandleTransact(transact)
 }
 
@@ -83,10 +103,27 @@ func (obj *Aggregate) PrintReport() {
 	fmt.Printf("Number of aggregated transact %.2f\n", obj.sum_transact)
 	if obj.tb.GetLen() > 0 {
 		fmt.Println("Await end aggregate:")
-		for _, item := range obj.tb.GetItems() {
+		for _, item := range getSortedItems(obj.tb) {
 			_, parts, _ := item.transact.GetParts()
 			fmt.Printf("transact %d wait %d parts\n", item.transact.GetId(), parts)
 		}
 	}
 	fmt.Println()
 }
+
+type aggregateState struct {
+	SumTransact float64
+}
+
+func (obj *Aggregate) SaveState() ([]byte, error) {
+	return encodeState(aggregateState{SumTransact: obj.sum_transact})
+}
+
+func (obj *Aggregate) LoadState(data []byte) error {
+	state := aggregateState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	obj.sum_transact = state.SumTransact
+	return nil
+}
@@ -30,15 +30,15 @@ func NewAssign(name string, parameters ...Parameter) *Assign {
 }
 
 func (obj *Assign) AppendTransact(transact ITransaction) bool {
-	transact.PrintInfo()
-	obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Assign")
-	for _, v := range obj.GetDst() {
-		if v.AppendTransact(transact) {
-			transact.SetParameters(obj.parameters)
-			return true
+	return journalPass(obj.name, transact, func() bool {
+		for _, v := range obj.GetDst() {
+			if SendTransact(obj, v, transact) {
+				transact.SetParameters(obj.parameters)
+				return true
+			}
 		}
-	}
-	return false
+		return false
+	})
 }
 
 func (obj *Assign) PrintReport() {
@@ -6,6 +6,8 @@ package gpss
 
 import (
 	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
 	"sync"
 )
 
@@ -22,6 +24,12 @@ type IBaseObj interface {
 	PrintReport()                       // Print report
 }
 
+// ITransactTabler implemented by objects which keep transacts in a transact
+// table
+type ITransactTabler interface {
+	GetTransactTable() ITransactTable // Get transact table of object
+}
+
 type BaseObj struct {
 	name    string
 	objTime int
@@ -29,6 +37,9 @@ type BaseObj struct {
 	pipe    IPipeline
 	tb      ITransactTable
 	id      int
+	rndMu   sync.Mutex // Mutex for random stream
+	rndSrc  *rand.PCG  // Source of random stream
+	rnd     *rand.Rand // Random stream of object
 }
 
 func NewBaseObj(name string) *BaseObj {
@@ -91,3 +102,63 @@ func (obj *BaseObj) HandleTransacts(wg *sync.WaitGroup) {
 func (obj *BaseObj) PrintReport() {
 	fmt.Println("Object name \"", obj.name, "\"")
 }
+
+// Get random stream of object. Stream is created at first call from seed of
+// pipeline and name of object, so each object has independent reproducible
+// stream.
+func (obj *BaseObj) getRandomStream() *rand.Rand {
+	if obj.rnd == nil {
+		h := fnv.New64a()
+		h.Write([]byte(obj.name))
+		var seed int64
+		if p, ok := obj.pipe.(interface{ GetSeed() int64 }); ok {
+			seed = p.GetSeed()
+		}
+		obj.rndSrc = rand.NewPCG(uint64(seed), h.Sum64())
+		obj.rnd = rand.New(obj.rndSrc)
+	}
+	return obj.rnd
+}
+
+// Generate random between min and max from random stream of object. Before
+// appending object to pipeline GetRandom function is used.
+func (obj *BaseObj) GetRandom(min, max int) int {
+	if obj.pipe == nil {
+		return GetRandom(min, max)
+	}
+	defer obj.rndMu.Unlock()
+	obj.rndMu.Lock()
+	return obj.getRandomStream().IntN(max-min+1) + min
+}
+
+// Get random bool from random stream of object
+func (obj *BaseObj) GetRandomBool() bool {
+	if obj.pipe == nil {
+		return GetRandomBool()
+	}
+	defer obj.rndMu.Unlock()
+	obj.rndMu.Lock()
+	return obj.getRandomStream().Float32() < 0.5
+}
+
+// Save state of random stream
+func (obj *BaseObj) saveRandom() ([]byte, error) {
+	defer obj.rndMu.Unlock()
+	obj.rndMu.Lock()
+	if obj.pipe == nil {
+		return nil, nil
+	}
+	obj.getRandomStream()
+	return obj.rndSrc.MarshalBinary()
+}
+
+// Load state of random stream
+func (obj *BaseObj) loadRandom(data []byte) error {
+	defer obj.rndMu.Unlock()
+	obj.rndMu.Lock()
+	if len(data) == 0 || obj.pipe == nil {
+		return nil
+	}
+	obj.getRandomStream()
+	return obj.rndSrc.UnmarshalBinary(data)
+}
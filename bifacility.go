@@ -20,8 +20,10 @@ type InFacility struct {
 	bakupFacilityName string
 	// For counting the transacts that go through Bifacility
 	cnt_transact float64
-	// For counting the advance of transact
-	sum_advance float64
+	// Busy state of facility, 1 - busy, 0 - empty
+	busy *TimeWeighted
+	// Quantiles of holding times
+	holding *QuantileSketch
 	// For saving time of input transact in Bifacility
 	timeOfInput int
 }
@@ -38,6 +40,8 @@ type OutFacility struct {
 func NewBifacility(name string) (*InFacility, *OutFacility) {
 	inObj := &InFacility{}
 	inObj.BaseObj.Init(name)
+	inObj.busy = NewTimeWeighted(0, 0)
+	inObj.holding = NewQuantileSketch(DefaultQuantileAccuracy, DefaultQuantileBuckets)
 	outObj := &OutFacility{}
 	outObj.name = name + "_OUT"
 	outObj.tb = inObj.tb
@@ -46,9 +50,8 @@ func NewBifacility(name string) (*InFacility, *OutFacility) {
 }
 
 func (obj *InFacility) HandleTransact(transact ITransaction) {
-	transact.PrintInfo()
 	for _, v := range obj.GetDst() {
-		if v.AppendTransact(transact) {
+		if SendTransact(obj, v, transact) {
 			return
 		}
 	}
@@ -59,7 +62,6 @@ func (obj *InFacility) AppendTransact(transact ITransaction) bool {
 		// Facility is busy
 		return false
 	}
-	obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Facility")
 	transact.SetHolderName(obj.name)
 	if transact.GetParameterByName("Facility") != nil {
 		obj.bakupFacilityName = transact.GetParameterByName("Facility").(string)
@@ -69,21 +71,35 @@ func (obj *InFacility) AppendTransact(transact ITransaction) bool {
 	obj.tb.Push(transact)
 	obj.cnt_transact++
 	obj.timeOfInput = obj.GetPipeline().GetModelTime()
+	obj.busy.Update(obj.timeOfInput, 1)
 	obj.HandleTransact(transact)
 	return true
 }
 
+// Get time-weighted statistics of busy state of facility, mean is utilization
+func (obj *InFacility) GetBusyStat() *TimeWeighted {
+	return obj.busy
+}
+
+// Get quantiles of holding times of facility
+func (obj *InFacility) GetHoldingStat() *QuantileSketch {
+	return obj.holding
+}
+
 func (obj *InFacility) PrintReport() {
 	obj.BaseObj.PrintReport()
-	avr := obj.sum_advance / obj.cnt_transact
+	modelTime := obj.GetPipeline().GetModelTime()
+	avr := obj.busy.GetArea(modelTime) / obj.cnt_transact
 	fmt.Printf("Average advance %.2f \tAverage utilization %.2f%%\tNumber entries %.2f \t", avr,
-		100*avr*obj.cnt_transact/float64(obj.GetPipeline().GetSimTime()), obj.cnt_transact)
+		100*obj.busy.GetMean(modelTime), obj.cnt_transact)
 	if obj.HoldedTransactID > 0 {
 		fmt.Print("Transact ", obj.HoldedTransactID, " in facility")
 	} else {
 		fmt.Print("Facility is empty")
 	}
-	fmt.Printf("\n\n")
+	fmt.Println()
+	obj.holding.PrintReport("Holding time")
+	fmt.Println()
 }
 
 func (obj *InFacility) IsEmpty() bool {
@@ -95,7 +111,6 @@ func (obj *InFacility) IsEmpty() bool {
 }
 
 func (obj *OutFacility) HandleTransact(transact ITransaction) {
-	transact.PrintInfo()
 	if obj.inFacility.bakupFacilityName != "" {
 		transact.SetParameters([]Parameter{{Name: "Facility",
 			Value: obj.inFacility.bakupFacilityName}})
@@ -104,11 +119,12 @@ func (obj *OutFacility) HandleTransact(transact ITransaction) {
 	}
 
 	for _, v := range obj.GetDst() {
-		if v.AppendTransact(transact) {
-			advance := obj.GetPipeline().GetModelTime() - obj.inFacility.timeOfInput
-			obj.inFacility.sum_advance += float64(advance)
+		if SendTransact(obj, v, transact) {
+			modelTime := obj.GetPipeline().GetModelTime()
+			obj.inFacility.holding.Add(float64(modelTime - obj.inFacility.timeOfInput))
 			obj.tb.Remove(transact)
 			obj.inFacility.HoldedTransactID = -1
+			obj.inFacility.busy.Update(modelTime, 0)
 			return
 		}
 	}
@@ -119,7 +135,6 @@ func (obj *OutFacility) AppendTransact(transact ITransaction) bool {
 	if obj.inFacility.HoldedTransactID != transact.GetId() {
 		return false
 	}
-	obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Facility")
 	obj.HandleTransact(transact)
 	if obj.tb.GetLen() == 0 {
 		return true
@@ -130,3 +145,37 @@ func (obj *OutFacility) AppendTransact(transact ITransaction) bool {
 func (obj *OutFacility) PrintReport() {
 	return
 }
+
+type inFacilityState struct {
+	HoldedTransactID  int
+	BakupFacilityName string
+	CntTransact       float64
+	Busy              timeWeightedState
+	Holding           quantileSketchState
+	TimeOfInput       int
+}
+
+func (obj *InFacility) SaveState() ([]byte, error) {
+	return encodeState(inFacilityState{
+		HoldedTransactID:  obj.HoldedTransactID,
+		BakupFacilityName: obj.bakupFacilityName,
+		CntTransact:       obj.cnt_transact,
+		Busy:              obj.busy.getState(),
+		Holding:           obj.holding.getState(),
+		TimeOfInput:       obj.timeOfInput,
+	})
+}
+
+func (obj *InFacility) LoadState(data []byte) error {
+	state := inFacilityState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	obj.HoldedTransactID = state.HoldedTransactID
+	obj.bakupFacilityName = state.BakupFacilityName
+	obj.cnt_transact = state.CntTransact
+	obj.busy.setState(state.Busy)
+	obj.holding.setState(state.Holding)
+	obj.timeOfInput = state.TimeOfInput
+	return nil
+}
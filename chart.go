@@ -0,0 +1,240 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Size of charts
+const (
+	ChartWidth  = 640
+	ChartHeight = 400
+)
+
+// Margins of plot area of chart
+const (
+	chartLeft   = 60
+	chartRight  = 20
+	chartTop    = 40
+	chartBottom = 60
+)
+
+// Colors of series of charts
+var chartColors = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f",
+	"#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// Series of values for line chart
+type Series struct {
+	Name   string
+	Times  []int     // Model times
+	Values []float64 // Values in the same order as times
+}
+
+// Writer of SVG chart with plot area and axes
+type svgChart struct {
+	w          *bufio.Writer
+	minX, maxX float64 // Range of values by X
+	maxY       float64 // Max value by Y, min is 0
+}
+
+func newSVGChart(w io.Writer, title string, minX, maxX, maxY float64) *svgChart {
+	if maxX <= minX {
+		maxX = minX + 1
+	}
+	if maxY <= 0 {
+		maxY = 1
+	}
+	c := &svgChart{w: bufio.NewWriter(w), minX: minX, maxX: maxX, maxY: maxY}
+	fmt.Fprintf(c.w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n",
+		ChartWidth, ChartHeight, ChartWidth, ChartHeight)
+	fmt.Fprintf(c.w, `<rect width="%d" height="%d" fill="white"/>`+"\n", ChartWidth, ChartHeight)
+	fmt.Fprintf(c.w, `<text x="%d" y="20" text-anchor="middle" font-size="14">%s</text>`+"\n",
+		ChartWidth/2, html.EscapeString(title))
+	// Axes and horizontal grid
+	for i := 0; i <= 4; i++ {
+		v := maxY * float64(i) / 4
+		y := c.y(v)
+		fmt.Fprintf(c.w, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#ddd"/>`+"\n",
+			chartLeft, y, ChartWidth-chartRight, y)
+		fmt.Fprintf(c.w, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`+"\n",
+			chartLeft-5, y+4, formatChartValue(v))
+	}
+	fmt.Fprintf(c.w, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n",
+		chartLeft, chartTop, chartLeft, ChartHeight-chartBottom)
+	fmt.Fprintf(c.w, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n",
+		chartLeft, ChartHeight-chartBottom, ChartWidth-chartRight, ChartHeight-chartBottom)
+	return c
+}
+
+// Get coordinate of value by X
+func (c *svgChart) x(v float64) float64 {
+	return chartLeft + (v-c.minX)/(c.maxX-c.minX)*(ChartWidth-chartLeft-chartRight)
+}
+
+// Get coordinate of value by Y
+func (c *svgChart) y(v float64) float64 {
+	return ChartHeight - chartBottom - v/c.maxY*(ChartHeight-chartTop-chartBottom)
+}
+
+// Write label under axis X
+func (c *svgChart) labelX(x float64, label string) {
+	fmt.Fprintf(c.w, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n",
+		x, ChartHeight-chartBottom+15, html.EscapeString(label))
+}
+
+func (c *svgChart) bar(x, width, value float64, color, title string) {
+	fmt.Fprintf(c.w, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s</title></rect>`+"\n",
+		x, c.y(value), width, c.y(0)-c.y(value), color, html.EscapeString(title))
+}
+
+func (c *svgChart) close() error {
+	fmt.Fprintln(c.w, "</svg>")
+	return c.w.Flush()
+}
+
+func formatChartValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e9 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'g', 3, 64)
+}
+
+// Write histogram of frequency classes of table, for example life of
+// transacts or times in queue
+func WriteHistogramSVG(w io.Writer, title string, table *Table) error {
+	classes := table.GetClasses()
+	maxY := 0.0
+	for _, c := range classes {
+		maxY = math.Max(maxY, float64(c.Frequency))
+	}
+	chart := newSVGChart(w, title, 0, float64(len(classes)), maxY)
+	from := "-"
+	for i, c := range classes {
+		to := "_"
+		if i < len(classes)-1 {
+			to = formatChartValue(c.Upper)
+		}
+		chart.bar(chart.x(float64(i))+1, chart.x(1)-chart.x(0)-2, float64(c.Frequency), chartColors[0],
+			fmt.Sprintf("%s - %s: %d (%.2f%%)", from, to, c.Frequency, c.Percent))
+		if i < len(classes)-1 {
+			chart.labelX(chart.x(float64(i+1)), to)
+		}
+		from = to
+	}
+	return chart.close()
+}
+
+// Write bar chart of values with labels, maxValue is upper bound of axis Y,
+// 0 - max of values. Labels and values must have the same length.
+func WriteBarChartSVG(w io.Writer, title string, labels []string, values []float64, maxValue float64) error {
+	if len(labels) != len(values) {
+		return fmt.Errorf("chart %q: %d labels for %d values", title, len(labels), len(values))
+	}
+	if maxValue <= 0 {
+		for _, v := range values {
+			maxValue = math.Max(maxValue, v)
+		}
+	}
+	chart := newSVGChart(w, title, 0, float64(len(values)), maxValue)
+	for i, v := range values {
+		width := chart.x(1) - chart.x(0)
+		chart.bar(chart.x(float64(i))+width*0.1, width*0.8, v, chartColors[i%len(chartColors)],
+			fmt.Sprintf("%s: %s", labels[i], formatChartValue(v)))
+		chart.labelX(chart.x(float64(i)+0.5), labels[i])
+	}
+	return chart.close()
+}
+
+// Write line chart of series, for example samples of queue lengths
+func WriteLineChartSVG(w io.Writer, title string, series ...Series) error {
+	minX, maxX, maxY := math.Inf(1), math.Inf(-1), 0.0
+	for _, s := range series {
+		for i, t := range s.Times {
+			minX = math.Min(minX, float64(t))
+			maxX = math.Max(maxX, float64(t))
+			maxY = math.Max(maxY, s.Values[i])
+		}
+	}
+	if math.IsInf(minX, 0) {
+		minX, maxX = 0, 1
+	}
+	chart := newSVGChart(w, title, minX, maxX, maxY)
+	for i := 0; i <= 4; i++ {
+		t := minX + (maxX-minX)*float64(i)/4
+		chart.labelX(chart.x(t), formatChartValue(math.Round(t)))
+	}
+	for i, s := range series {
+		color := chartColors[i%len(chartColors)]
+		fmt.Fprintf(chart.w, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="`, color)
+		for j, t := range s.Times {
+			fmt.Fprintf(chart.w, "%.1f,%.1f ", chart.x(float64(t)), chart.y(s.Values[j]))
+		}
+		fmt.Fprintf(chart.w, `"><title>%s</title></polyline>`+"\n", html.EscapeString(s.Name))
+		// Legend
+		y := ChartHeight - chartBottom + 30 + 12*(i/4)
+		x := chartLeft + (i%4)*150
+		fmt.Fprintf(chart.w, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`+"\n", x, y-9, color)
+		fmt.Fprintf(chart.w, `<text x="%d" y="%d">%s</text>`+"\n", x+14, y, html.EscapeString(s.Name))
+	}
+	return chart.close()
+}
+
+// Write bar chart of utilization of facilities and bifacilities
+func (p *Pipeline) WriteUtilizationSVG(w io.Writer) error {
+	var labels []string
+	var values []float64
+	for _, o := range p.GetSortedObjects() {
+		switch obj := o.(type) {
+		case *Facility:
+			labels = append(labels, obj.name)
+			values = append(values, obj.busy.GetMean(p.modelTime))
+		case *InFacility:
+			labels = append(labels, obj.name)
+			values = append(values, obj.busy.GetMean(p.modelTime))
+		}
+	}
+	return WriteBarChartSVG(w, "Utilization of facilities", labels, values, 1)
+}
+
+// Count of classes of histograms drawn from quantile sketches
+const sketchHistogramClasses = 10
+
+// Write histogram of times in queue: by table of times in queue if it is set,
+// else by quantile sketch of times
+func (obj *Queue) WriteTimeHistogramSVG(w io.Writer) error {
+	title := obj.name + " time in queue"
+	table := obj.qtable
+	if table == nil {
+		table = obj.times.GetTable(title, sketchHistogramClasses)
+	}
+	return WriteHistogramSVG(w, title, table)
+}
+
+// Write histogram of life of killed transacts by quantile sketch of life
+func (obj *Hole) WriteLifeHistogramSVG(w io.Writer) error {
+	title := obj.name + " life"
+	return WriteHistogramSVG(w, title, obj.life.GetTable(title, sketchHistogramClasses))
+}
+
+// Get all recorded series
+func (s *Sampler) GetAllSeries() []Series {
+	series := make([]Series, 0, len(s.probes))
+	for _, name := range s.GetNames() {
+		times, values := s.GetSeries(name)
+		series = append(series, Series{Name: name, Times: times, Values: values})
+	}
+	return series
+}
+
+// Write line chart of all recorded series
+func (s *Sampler) WriteSVG(w io.Writer, title string) error {
+	return WriteLineChartSVG(w, title, s.GetAllSeries()...)
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Check that SVG is well-formed XML and count elements by name
+func countSVGElements(t *testing.T, data []byte, name string) int {
+	count := 0
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			return count
+		}
+		if err != nil {
+			t.Fatal("SVG, expected well-formed XML, got", err)
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == name {
+			count++
+		}
+	}
+}
+
+func TestCharts(t *testing.T) {
+	table, err := NewTable("Life", 0, 10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []float64{1, 5, 12, 30, 100} {
+		table.Add(v)
+	}
+	var buf bytes.Buffer
+	if err := WriteHistogramSVG(&buf, "Life <of> transacts", table); err != nil {
+		t.Fatal(err)
+	}
+	// Background and one bar per class
+	if n := countSVGElements(t, buf.Bytes(), "rect"); n != 6 {
+		t.Error("Rects of histogram, expected", 6, "got", n)
+	}
+
+	buf.Reset()
+	series := Series{Name: "Chairs", Times: []int{0, 10, 20}, Values: []float64{0, 2, 1}}
+	if err := WriteLineChartSVG(&buf, "Queue", series); err != nil {
+		t.Fatal(err)
+	}
+	if n := countSVGElements(t, buf.Bytes(), "polyline"); n != 1 {
+		t.Error("Lines, expected", 1, "got", n)
+	}
+
+	buf.Reset()
+	if err := WriteBarChartSVG(&buf, "Bars", []string{"a", "b"}, []float64{1}, 0); err == nil {
+		t.Error("Bar chart with 2 labels for 1 value, expected error, got nil")
+	}
+	buf.Reset()
+	if err := WriteBarChartSVG(&buf, "Bars", []string{"a", "b"}, []float64{1, 2}, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Background and one bar per value
+	if n := countSVGElements(t, buf.Bytes(), "rect"); n != 3 {
+		t.Error("Rects of bar chart, expected", 3, "got", n)
+	}
+
+	buf.Reset()
+	pipe := NewPipeline("pipe", false)
+	f := NewFacility("Master", 16, 4)
+	in, out := NewBifacility("Table")
+	pipe.Append(f)
+	pipe.Append(in, out)
+	pipe.Append(out)
+	if err := pipe.WriteUtilizationSVG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), ">Master<") || !strings.Contains(buf.String(), ">Table<") {
+		t.Error("Utilization chart, expected labels of facilities, got", buf.String())
+	}
+	// Default histograms by quantile sketches
+	pipe = NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 5, 0, 0, 0, nil)
+	q := NewQueue("Chairs")
+	f = NewFacility("Master", 7, 0)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	pipe.SetSimTime(100)
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+	life := h.GetLifeStat().GetTable("Life", 10)
+	if life.GetEntries() != h.GetLifeStat().GetCount() || life.GetEntries() == 0 {
+		t.Error("Entries of life table, expected", h.GetLifeStat().GetCount(), "got", life.GetEntries())
+	}
+	for _, write := range []func(w io.Writer) error{q.WriteTimeHistogramSVG, h.WriteLifeHistogramSVG} {
+		buf.Reset()
+		if err := write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if n := countSVGElements(t, buf.Bytes(), "rect"); n != 11 {
+			t.Error("Rects of histogram, expected", 11, "got", n)
+		}
+	}
+}
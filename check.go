@@ -55,22 +55,23 @@ func NewCheck(name string, hndl HandleCheckingFunc, falseObj IBaseObj, parameter
 }
 
 func (obj *Check) AppendTransact(transact ITransaction) bool {
-	transact.PrintInfo()
-	obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Check")
+	return journalPass(obj.name, transact, func() bool {
+		return obj.sendTransact(transact)
+	})
+}
+
+// Send transact to destination based on the result of checking
+func (obj *Check) sendTransact(transact ITransaction) bool {
 	if !obj.HandleChecking(obj, transact) {
 		obj.cnt_false++
 		if obj.falseObj != nil {
-			if obj.falseObj.AppendTransact(transact) {
-				return true
-			} else {
-				return false
-			}
+			return SendTransact(obj, obj.falseObj, transact)
 		}
 		return false
 	}
 	obj.cnt_true++
 	for _, v := range obj.GetDst() {
-		if v.AppendTransact(transact) {
+		if SendTransact(obj, v, transact) {
 			return true
 		}
 	}
@@ -82,3 +83,21 @@ func (obj *Check) PrintReport() {
 	fmt.Printf("Check result true %d\tCheck result false %d\n\n", obj.cnt_true, obj.cnt_false)
 	return
 }
+
+type checkState struct {
+	CntTrue  int
+	CntFalse int
+}
+
+func (obj *Check) SaveState() ([]byte, error) {
+	return encodeState(checkState{CntTrue: obj.cnt_true, CntFalse: obj.cnt_false})
+}
+
+func (obj *Check) LoadState(data []byte) error {
+	state := checkState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	obj.cnt_true, obj.cnt_false = state.CntTrue, state.CntFalse
+	return nil
+}
@@ -0,0 +1,285 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// IStateObj implemented by objects which have own state (counters,
+// statistics) for saving in checkpoint
+type IStateObj interface {
+	SaveState() ([]byte, error)  // Save state of object
+	LoadState(data []byte) error // Load state of object
+}
+
+// Checkpoint is a full state of simulation. Parameters of transacts are
+// encoded by encoding/gob, so custom types of parameters must be registered
+// by gob.Register.
+type Checkpoint struct {
+	ModelTime  int                      // Current model time
+	SimTime    int                      // Simulation time
+	TransactID int                      // ID of last transaction
+	Seed       int64                    // Seed of random streams
+	Transacts  []TransactState          // All transacts in tables of objects
+	Objects    map[string]*ObjectState  // States of objects by names
+	Counters   map[string]BlockCounters // Counters of objects by names
+	Tables     map[string][]byte        // States of tables by names
+}
+
+// State of transact
+type TransactState struct {
+	ID         int
+	Born       int
+	Rip        int
+	Advance    int
+	Ticks      int
+	HolderName string
+	HolderTime int
+	TimeQueue  int
+	Part       int
+	Parts      int
+	ParentID   int
+	Parameters map[string]interface{}
+	Journal    []JournalRecord
+}
+
+// State of object
+type ObjectState struct {
+	Table  *TableState // Transact table, nil if object has no table
+	Random []byte      // State of random stream
+	State  []byte      // Own state of object, see IStateObj
+}
+
+// State of transact table
+type TableState struct {
+	FirstID int
+	LastID  int
+	Items   []TableItemState
+}
+
+// State of item of transact table
+type TableItemState struct {
+	Transact   int // Index of transact in Checkpoint.Transacts
+	PrevoiseID int
+	NextID     int
+}
+
+// Encode state of object by encoding/gob
+func encodeState(state interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode state of object by encoding/gob
+func decodeState(data []byte, state interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(state)
+}
+
+// Save checkpoint of simulation to w. Simulation must be stopped or paused
+// between steps.
+func (p *Pipeline) SaveCheckpoint(w io.Writer) error {
+	cp := &Checkpoint{
+		ModelTime:  p.modelTime,
+		SimTime:    p.simTime,
+		TransactID: p.id,
+		Seed:       p.seed,
+		Objects:    make(map[string]*ObjectState),
+		Counters:   p.saveCounters(),
+		Tables:     make(map[string][]byte),
+	}
+	// Transacts may be kept in several tables (for example, by Bifacility and
+	// Advance), so they are saved once and tables keep indexes
+	indexes := make(map[ITransaction]int)
+	index := func(transact ITransaction) (int, error) {
+		if i, ok := indexes[transact]; ok {
+			return i, nil
+		}
+		t, ok := transact.(*Transaction)
+		if !ok {
+			return 0, fmt.Errorf("unsupported transact type %T", transact)
+		}
+		cp.Transacts = append(cp.Transacts, TransactState{
+			ID:         t.id,
+			Born:       t.born,
+			Rip:        t.rip,
+			Advance:    t.advance,
+			Ticks:      t.ticks,
+			HolderName: t.holderName,
+			HolderTime: t.holderTime,
+			TimeQueue:  t.timequeue,
+			Part:       t.parts.part,
+			Parts:      t.parts.parts,
+			ParentID:   t.parts.parent_id,
+			Parameters: t.parameters,
+			Journal:    t.journal,
+		})
+		indexes[transact] = len(cp.Transacts) - 1
+		return len(cp.Transacts) - 1, nil
+	}
+
+	for _, o := range p.GetSortedObjects() {
+		st := &ObjectState{}
+		if tabler, ok := o.(ITransactTabler); ok {
+			if tb, ok := tabler.GetTransactTable().(*TransactTable); ok && tb != nil {
+				tb.mu.Lock()
+				st.Table = &TableState{FirstID: tb.firstID, LastID: tb.lastID}
+				for _, item := range tb.mp {
+					i, err := index(item.transact)
+					if err != nil {
+						tb.mu.Unlock()
+						return err
+					}
+					st.Table.Items = append(st.Table.Items,
+						TableItemState{Transact: i, PrevoiseID: item.prevoiseID, NextID: item.nextID})
+				}
+				tb.mu.Unlock()
+			}
+		}
+		if r, ok := o.(interface{ saveRandom() ([]byte, error) }); ok {
+			data, err := r.saveRandom()
+			if err != nil {
+				return err
+			}
+			st.Random = data
+		}
+		if s, ok := o.(IStateObj); ok {
+			data, err := s.SaveState()
+			if err != nil {
+				return fmt.Errorf("save state of %q: %v", o.GetName(), err)
+			}
+			st.State = data
+		}
+		cp.Objects[o.GetName()] = st
+	}
+	for _, t := range p.GetTables() {
+		data, err := t.SaveState()
+		if err != nil {
+			return fmt.Errorf("save state of table %q: %v", t.GetName(), err)
+		}
+		cp.Tables[t.GetName()] = data
+	}
+	return gob.NewEncoder(w).Encode(cp)
+}
+
+// Load checkpoint of simulation from r. Pipeline must contain the same objects
+// as pipeline which saved checkpoint, after loading simulation can be
+// continued by Start.
+func (p *Pipeline) LoadCheckpoint(r io.Reader) error {
+	cp := &Checkpoint{}
+	if err := gob.NewDecoder(r).Decode(cp); err != nil {
+		return err
+	}
+	for name := range cp.Objects {
+		if p.objects[name] == nil {
+			return fmt.Errorf("object %q not found in pipeline", name)
+		}
+	}
+	transacts := make([]ITransaction, len(cp.Transacts))
+	for i, ts := range cp.Transacts {
+		t := &Transaction{
+			id:         ts.ID,
+			born:       ts.Born,
+			rip:        ts.Rip,
+			advance:    ts.Advance,
+			ticks:      ts.Ticks,
+			holderName: ts.HolderName,
+			holderTime: ts.HolderTime,
+			timequeue:  ts.TimeQueue,
+			pipe:       p,
+			parts:      Parts{ts.Part, ts.Parts, ts.ParentID},
+			parameters: ts.Parameters,
+			journal:    ts.Journal,
+		}
+		if t.parameters == nil {
+			t.parameters = make(map[string]interface{})
+		}
+		transacts[i] = t
+	}
+
+	for _, o := range p.GetSortedObjects() {
+		st := cp.Objects[o.GetName()]
+		if st == nil {
+			return fmt.Errorf("object %q not found in checkpoint", o.GetName())
+		}
+		if tabler, ok := o.(ITransactTabler); ok && st.Table != nil {
+			if tb, ok := tabler.GetTransactTable().(*TransactTable); ok && tb != nil {
+				tb.mu.Lock()
+				tb.firstID = st.Table.FirstID
+				tb.lastID = st.Table.LastID
+				tb.mp = make(map[int]*TableItem)
+				for _, item := range st.Table.Items {
+					transact := transacts[item.Transact]
+					tb.mp[transact.GetId()] = &TableItem{transact: transact,
+						prevoiseID: item.PrevoiseID, nextID: item.NextID}
+				}
+				tb.mu.Unlock()
+			}
+		}
+		if r, ok := o.(interface{ loadRandom([]byte) error }); ok {
+			if err := r.loadRandom(st.Random); err != nil {
+				return err
+			}
+		}
+		if s, ok := o.(IStateObj); ok && st.State != nil {
+			if err := s.LoadState(st.State); err != nil {
+				return fmt.Errorf("load state of %q: %v", o.GetName(), err)
+			}
+		}
+	}
+
+	for _, t := range p.GetTables() {
+		data, ok := cp.Tables[t.GetName()]
+		if !ok {
+			return fmt.Errorf("table %q not found in checkpoint", t.GetName())
+		}
+		if err := t.LoadState(data); err != nil {
+			return fmt.Errorf("load state of table %q: %v", t.GetName(), err)
+		}
+	}
+
+	p.modelTime = cp.ModelTime
+	p.simTime = cp.SimTime
+	p.id = cp.TransactID
+	p.seed = cp.Seed
+	p.loadCounters(cp.Counters)
+	return nil
+}
+
+// Save checkpoint of simulation to file. File is replaced atomically, so
+// previous checkpoint is kept in case of crash while saving.
+func (p *Pipeline) SaveCheckpointFile(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := p.SaveCheckpoint(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Load checkpoint of simulation from file
+func (p *Pipeline) LoadCheckpointFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return p.LoadCheckpoint(f)
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func newCheckpointPipeline() (*Pipeline, *InFacility, *Advance, *Hole) {
+	p := NewPipeline("Barbershop", false)
+	g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+	q := NewQueue("Chairs")
+	f_in, f_out := NewBifacility("Master")
+	a := NewAdvance("Master work", 16, 4)
+	h := NewHole("Out")
+	p.Append(g, q)
+	p.Append(q, f_in)
+	p.Append(f_in, a)
+	p.Append(a, f_out)
+	p.Append(f_out, h)
+	p.Append(h)
+	p.SetSimTime(480)
+	return p, f_in, a, h
+}
+
+func TestPipeline_Checkpoint(t *testing.T) {
+	p1, in1, _, h1 := newCheckpointPipeline()
+	p1.SetSeed(1)
+	for p1.GetModelTime() < 200 {
+		p1.Step()
+	}
+	var buf bytes.Buffer
+	if err := p1.SaveCheckpoint(&buf); err != nil {
+		t.Fatal("Save checkpoint, got error", err)
+	}
+
+	p2, in2, a2, h2 := newCheckpointPipeline()
+	if err := p2.LoadCheckpoint(&buf); err != nil {
+		t.Fatal("Load checkpoint, got error", err)
+	}
+	if p2.GetModelTime() != p1.GetModelTime() {
+		t.Error("Model time, expected", p1.GetModelTime(), "got", p2.GetModelTime())
+	}
+	if p2.GetSeed() != 1 {
+		t.Error("Seed, expected", 1, "got", p2.GetSeed())
+	}
+	if p2.GetIDNewTransaction() != p1.GetIDNewTransaction() {
+		t.Error("ID of new transaction is not restored")
+	}
+	if h2.cnt_transact != h1.cnt_transact {
+		t.Error("Killed, expected", h1.cnt_transact, "got", h2.cnt_transact)
+	}
+	if in2.HoldedTransactID != in1.HoldedTransactID {
+		t.Error("Holded transact, expected", in1.HoldedTransactID, "got", in2.HoldedTransactID)
+	}
+	// Transact holded by bifacility is the same transact as in advance
+	if in2.HoldedTransactID > 0 {
+		holded := in2.tb.GetItem(in2.HoldedTransactID)
+		advanced := a2.tb.GetItem(in2.HoldedTransactID)
+		if holded == nil || advanced == nil || holded.transact != advanced.transact {
+			t.Error("Transact in bifacility and advance must be the same object")
+		}
+	}
+}
+
+func TestPipeline_CheckpointContinuation(t *testing.T) {
+	summary := func(p *Pipeline, in *InFacility, h *Hole) []float64 {
+		q := p.GetObjByName("Chairs").(*Queue)
+		return []float64{h.cnt_transact, h.sum_life, h.sum_advance, q.sum_Entries,
+			q.sum_zeroEntries, q.sum_timequeue, in.cnt_transact, float64(p.GetIDNewTransaction())}
+	}
+	p1, in1, _, h1 := newCheckpointPipeline()
+	p1.SetSeed(1)
+	for p1.GetModelTime() < 200 {
+		p1.Step()
+	}
+	var buf bytes.Buffer
+	if err := p1.SaveCheckpoint(&buf); err != nil {
+		t.Fatal("Save checkpoint, got error", err)
+	}
+	p2, in2, _, h2 := newCheckpointPipeline()
+	if err := p2.LoadCheckpoint(&buf); err != nil {
+		t.Fatal("Load checkpoint, got error", err)
+	}
+	for _, p := range []*Pipeline{p1, p2} {
+		for p.GetModelTime() < 480 {
+			p.Step()
+		}
+	}
+	expected := summary(p1, in1, h1)
+	if got := summary(p2, in2, h2); !reflect.DeepEqual(got, expected) {
+		t.Error("Run restored from checkpoint, expected", expected, "got", got)
+	}
+}
@@ -0,0 +1,90 @@
+// Interactive debugger for simulation models. Only models listed below are
+// available, own model can be debugged by gpss.RunDebugger from its main.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	. "github.com/soldatov-s/go-gpss"
+)
+
+// Models available for debugging
+var models = map[string]func() *Pipeline{
+	"barbershop": func() *Pipeline {
+		p := NewPipeline("Barbershop", false)
+		g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+		q := NewQueue("Chairs")
+		f := NewFacility("Master", 16, 4)
+		h := NewHole("Out")
+		p.Append(g, q)
+		p.Append(q, f)
+		p.Append(f, h)
+		p.Append(h)
+		return p
+	},
+	"barbershop-bifacility": func() *Pipeline {
+		p := NewPipeline("Barbershop", false)
+		g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+		q := NewQueue("Chairs")
+		f_in, f_out := NewBifacility("Master")
+		a := NewAdvance("Master work", 16, 4)
+		h := NewHole("Out")
+		p.Append(g, q)
+		p.Append(q, f_in)
+		p.Append(f_in, a)
+		p.Append(a, f_out)
+		p.Append(f_out, h)
+		p.Append(h)
+		return p
+	},
+	"wc": func() *Pipeline {
+		p := NewPipeline("Water Closet Simulation", false)
+		g := NewGenerator("Office", 0, 0, 0, 10, nil)
+		a1 := NewAdvance("Wanted to use the toilet", 90, 60)
+		a2 := NewAdvance("Path to WC", 5, 3)
+		q := NewQueue("Queue to the WC")
+		f1 := NewFacility("WC1", 15, 10)
+		f2 := NewFacility("WC2", 15, 10)
+		a3 := NewAdvance("Path from WC", 5, 3)
+		p.Append(g, a1)
+		p.Append(a1, a2)
+		p.Append(a2, q)
+		p.Append(q, f1, f2)
+		p.Append(f1, a3)
+		p.Append(f2, a3)
+		p.Append(a3, a1)
+		return p
+	},
+}
+
+func main() {
+	model := flag.String("model", "barbershop", "name of model")
+	simTime := flag.Int("time", 480, "simulation time")
+	list := flag.Bool("list", false, "list available models")
+	flag.Parse()
+
+	if *list {
+		names := make([]string, 0, len(models))
+		for name := range models {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	factory, ok := models[*model]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown model %q\n", *model)
+		os.Exit(1)
+	}
+	if err := RunDebugger(factory(), *simTime); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
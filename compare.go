@@ -0,0 +1,335 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Default confidence level of comparisons
+const DefaultConfidence = 0.95
+
+// Confidence interval for difference of means A-B
+type DiffInterval struct {
+	Method      string  `json:"method"` // paired-t or Welch
+	Diff        float64 `json:"diff"`
+	HalfWidth   float64 `json:"half_width"`
+	Lower       float64 `json:"lower"`
+	Upper       float64 `json:"upper"`
+	DF          float64 `json:"df"`          // Degrees of freedom
+	Significant bool    `json:"significant"` // Interval doesn't contain 0
+}
+
+// Comparison of statistic of object in two scenarios
+type Comparison struct {
+	Object     string       `json:"object"`
+	Statistic  string       `json:"statistic"`
+	A          int          `json:"a"` // Index of scenario A
+	B          int          `json:"b"` // Index of scenario B
+	MeanA      float64      `json:"mean_a"`
+	MeanB      float64      `json:"mean_b"`
+	Confidence float64      `json:"confidence"`
+	Paired     DiffInterval `json:"paired"`
+	Welch      DiffInterval `json:"welch"`
+}
+
+// Place of scenario in ranking by statistic
+type Ranking struct {
+	Rank       int      `json:"rank"`
+	Scenario   int      `json:"scenario"`
+	Parameters Scenario `json:"parameters"`
+	Mean       float64  `json:"mean"`
+	HalfWidth  float64  `json:"half_width"` // Half-width of confidence interval of mean
+	// Scenario isn't significantly worse than best one, difference with best
+	// is checked by paired-t intervals with Bonferroni correction
+	InBestSubset bool `json:"in_best_subset"`
+}
+
+// Get mean and sample variance
+func meanVariance(values []float64) (float64, float64) {
+	var mean, variance float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, variance / float64(len(values)-1)
+}
+
+// Paired-t confidence interval for difference of means of a and b. Values
+// with same index must be from runs with same random numbers.
+func PairedT(a, b []float64, confidence float64) (DiffInterval, error) {
+	if len(a) != len(b) {
+		return DiffInterval{}, fmt.Errorf("paired-t: counts of values differ, %d and %d", len(a), len(b))
+	}
+	if len(a) < 2 {
+		return DiffInterval{}, fmt.Errorf("paired-t: at least 2 pairs are required, got %d", len(a))
+	}
+	diffs := make([]float64, len(a))
+	for i := range a {
+		diffs[i] = a[i] - b[i]
+	}
+	mean, variance := meanVariance(diffs)
+	df := float64(len(a) - 1)
+	hw := StudentTQuantile(1-(1-confidence)/2, df) * math.Sqrt(variance/float64(len(a)))
+	return newDiffInterval("paired-t", mean, hw, df), nil
+}
+
+// Welch confidence interval for difference of means of independent a and b
+func Welch(a, b []float64, confidence float64) (DiffInterval, error) {
+	if len(a) < 2 || len(b) < 2 {
+		return DiffInterval{}, fmt.Errorf("Welch: at least 2 values are required, got %d and %d", len(a), len(b))
+	}
+	meanA, varA := meanVariance(a)
+	meanB, varB := meanVariance(b)
+	sa, sb := varA/float64(len(a)), varB/float64(len(b))
+	se := math.Sqrt(sa + sb)
+	// Welch-Satterthwaite degrees of freedom
+	df := float64(len(a) + len(b) - 2)
+	if sa+sb > 0 {
+		df = (sa + sb) * (sa + sb) / (sa*sa/float64(len(a)-1) + sb*sb/float64(len(b)-1))
+	}
+	hw := StudentTQuantile(1-(1-confidence)/2, df) * se
+	return newDiffInterval("Welch", meanA-meanB, hw, df), nil
+}
+
+func newDiffInterval(method string, diff, hw, df float64) DiffInterval {
+	return DiffInterval{Method: method, Diff: diff, HalfWidth: hw, Lower: diff - hw, Upper: diff + hw,
+		DF: df, Significant: diff-hw > 0 || diff+hw < 0}
+}
+
+// Quantile of Student's t-distribution with df degrees of freedom
+func StudentTQuantile(p, df float64) float64 {
+	if p <= 0 || p >= 1 || df <= 0 {
+		return math.NaN()
+	}
+	if p < 0.5 {
+		return -StudentTQuantile(1-p, df)
+	}
+	if p == 0.5 {
+		return 0
+	}
+	// Bisection on distribution function
+	lo, hi := 0.0, 1.0
+	for studentTCDF(hi, df) < p {
+		lo, hi = hi, hi*2
+	}
+	for i := 0; i < 200 && hi-lo > 1e-12*hi; i++ {
+		mid := (lo + hi) / 2
+		if studentTCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// Distribution function of Student's t-distribution
+func studentTCDF(t, df float64) float64 {
+	tail := 0.5 * incompleteBeta(df/2, 0.5, df/(df+t*t))
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// Regularized incomplete beta function I_x(a, b)
+func incompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// Continued fraction for incomplete beta function, by modified Lentz's method
+func betaContinuedFraction(a, b, x float64) float64 {
+	const tiny = 1e-300
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= 300; m++ {
+		fm := float64(m)
+		for _, aa := range []float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + aa*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + aa/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
+
+// Get values of statistic of object in scenario by replications
+func (res *ExperimentResults) getReplications(scenario int, object, statistic string) map[int]float64 {
+	values := make(map[int]float64)
+	for _, r := range res.Rows {
+		if r.Scenario == scenario && r.Object == object && r.Statistic == statistic {
+			values[r.Replication] = r.Value
+		}
+	}
+	return values
+}
+
+// Get values of two scenarios for replications made in both of them
+func (res *ExperimentResults) getPairs(a, b int, object, statistic string) ([]float64, []float64) {
+	va := res.getReplications(a, object, statistic)
+	vb := res.getReplications(b, object, statistic)
+	var pa, pb []float64
+	for r := 0; r < res.Replications; r++ {
+		x, okA := va[r]
+		y, okB := vb[r]
+		if okA && okB {
+			pa = append(pa, x)
+			pb = append(pb, y)
+		}
+	}
+	return pa, pb
+}
+
+// Compare statistic of object in scenarios a and b. Paired-t interval uses
+// common random numbers of replications, Welch interval treats scenarios as
+// independent.
+func (res *ExperimentResults) Compare(a, b int, object, statistic string, confidence float64) (*Comparison, error) {
+	for _, s := range []int{a, b} {
+		if s < 0 || s >= len(res.Scenarios) {
+			return nil, fmt.Errorf("scenario %d not found", s)
+		}
+	}
+	va, vb := res.GetValues(a, object, statistic), res.GetValues(b, object, statistic)
+	if len(va) == 0 || len(vb) == 0 {
+		return nil, fmt.Errorf("statistic %q of object %q not found", statistic, object)
+	}
+	c := &Comparison{Object: object, Statistic: statistic, A: a, B: b, Confidence: confidence}
+	c.MeanA, _ = meanVariance(va)
+	c.MeanB, _ = meanVariance(vb)
+	var err error
+	pa, pb := res.getPairs(a, b, object, statistic)
+	if c.Paired, err = PairedT(pa, pb, confidence); err != nil {
+		return nil, err
+	}
+	if c.Welch, err = Welch(va, vb, confidence); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Rank scenarios by mean of statistic of object, from best to worst. Best
+// subset contains scenarios which aren't significantly worse than best one.
+// minimize - smaller value is better, for example average time in queue
+func (res *ExperimentResults) Rank(object, statistic string, confidence float64, minimize bool) ([]Ranking, error) {
+	var ranking []Ranking
+	for s := range res.Scenarios {
+		values := res.GetValues(s, object, statistic)
+		if len(values) == 0 {
+			continue
+		}
+		mean, variance := meanVariance(values)
+		r := Ranking{Scenario: s, Parameters: res.Scenarios[s], Mean: mean}
+		if len(values) > 1 {
+			r.HalfWidth = StudentTQuantile(1-(1-confidence)/2, float64(len(values)-1)) *
+				math.Sqrt(variance/float64(len(values)))
+		}
+		ranking = append(ranking, r)
+	}
+	if len(ranking) == 0 {
+		return nil, fmt.Errorf("statistic %q of object %q not found", statistic, object)
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if minimize {
+			return ranking[i].Mean < ranking[j].Mean
+		}
+		return ranking[i].Mean > ranking[j].Mean
+	})
+	// Bonferroni correction for comparisons with each other scenario
+	level := confidence
+	if len(ranking) > 2 {
+		level = 1 - (1-confidence)/float64(len(ranking)-1)
+	}
+	for i := range ranking {
+		ranking[i].Rank = i + 1
+		ranking[i].InBestSubset = true
+		for j := range ranking {
+			if i == j {
+				continue
+			}
+			pi, pj := res.getPairs(ranking[i].Scenario, ranking[j].Scenario, object, statistic)
+			diff, err := PairedT(pi, pj, level)
+			if err != nil {
+				continue
+			}
+			if (minimize && diff.Lower > 0) || (!minimize && diff.Upper < 0) {
+				ranking[i].InBestSubset = false
+				break
+			}
+		}
+	}
+	return ranking, nil
+}
+
+// Print comparison
+func (c *Comparison) PrintReport() {
+	fmt.Printf("Comparison of %q %s, scenario %d - scenario %d, confidence %g\n",
+		c.Object, c.Statistic, c.A, c.B, c.Confidence)
+	fmt.Printf("Means\t%g\t%g\n", c.MeanA, c.MeanB)
+	for _, d := range []DiffInterval{c.Paired, c.Welch} {
+		fmt.Printf("%s\tdiff %g\t[%g, %g]\tdf %.1f\tsignificant %v\n",
+			d.Method, d.Diff, d.Lower, d.Upper, d.DF, d.Significant)
+	}
+}
+
+// Print ranking of scenarios by statistic of object and comparisons of each
+// scenario with best one
+func (res *ExperimentResults) PrintComparison(object, statistic string, confidence float64, minimize bool) error {
+	ranking, err := res.Rank(object, statistic, confidence, minimize)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Ranking of scenarios by %q %s, confidence %g\n", object, statistic, confidence)
+	fmt.Println("Rank\tScenario\tMean\tHalf-width\tBest subset")
+	for _, r := range ranking {
+		fmt.Printf("%d\t%d\t%g\t%g\t%v\n", r.Rank, r.Scenario, r.Mean, r.HalfWidth, r.InBestSubset)
+	}
+	for _, r := range ranking[1:] {
+		c, err := res.Compare(r.Scenario, ranking[0].Scenario, object, statistic, confidence)
+		if err != nil {
+			return err
+		}
+		c.PrintReport()
+	}
+	return nil
+}
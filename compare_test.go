@@ -0,0 +1,112 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStudentTQuantile(t *testing.T) {
+	tests := []struct {
+		p, df, expected float64
+	}{
+		{0.975, 1, 12.7062047},
+		{0.975, 10, 2.2281389},
+		{0.95, 5, 2.0150484},
+		{0.025, 10, -2.2281389},
+		{0.995, 1000, 2.5807546},
+	}
+	for _, tt := range tests {
+		if q := StudentTQuantile(tt.p, tt.df); math.Abs(q-tt.expected) > 1e-6 {
+			t.Error("Quantile", tt.p, "df", tt.df, "expected", tt.expected, "got", q)
+		}
+	}
+}
+
+func TestPairedTAndWelch(t *testing.T) {
+	a := []float64{10, 12, 11, 13, 12}
+	b := []float64{9, 11, 10, 12, 10}
+	paired, err := PairedT(a, b, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Differences 1, 1, 1, 1, 2: mean 1.2, std dev 0.4472
+	if math.Abs(paired.Diff-1.2) > 1e-9 || math.Abs(paired.HalfWidth-2.7764451*0.4472136/math.Sqrt(5)) > 1e-6 {
+		t.Error("Paired-t, expected 1.2 +- 0.5553, got", paired.Diff, paired.HalfWidth)
+	}
+	if !paired.Significant {
+		t.Error("Paired-t, expected significant difference")
+	}
+	// Without pairing variance between replications hides difference
+	welch, err := Welch(a, b, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if welch.Significant || math.Abs(welch.DF-7.9) > 0.1 {
+		t.Error("Welch, expected not significant with df 7.9, got", welch)
+	}
+	if _, err := PairedT(a, b[:3], 0.95); err == nil {
+		t.Error("Paired-t of different counts, expected error")
+	}
+}
+
+func TestExperimentResults_Rank(t *testing.T) {
+	res := &ExperimentResults{Scenarios: []Scenario{{"Waiters": 7}, {"Waiters": 8}, {"Waiters": 9}},
+		Replications: 4}
+	values := [][]float64{{20, 25, 22, 30}, {10, 16, 12, 19}, {12, 17, 13, 19}}
+	for s, v := range values {
+		for r, x := range v {
+			res.Rows = append(res.Rows, ExperimentRow{Scenario: s, Parameters: res.Scenarios[s],
+				Replication: r, Object: "Queue", Statistic: "ave_time", Value: x})
+		}
+	}
+	ranking, err := res.Rank("Queue", "ave_time", 0.95, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking[0].Scenario != 1 || ranking[2].Scenario != 0 {
+		t.Error("Ranking, expected scenarios 1, 2, 0, got", ranking)
+	}
+	if !ranking[0].InBestSubset || !ranking[1].InBestSubset || ranking[2].InBestSubset {
+		t.Error("Best subset, expected scenarios 1 and 2, got", ranking)
+	}
+	c, err := res.Compare(1, 0, "Queue", "ave_time", 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Paired.Diff != -10 || !c.Paired.Significant {
+		t.Error("Comparison of 8 and 7 waiters, expected significant -10, got", c.Paired)
+	}
+}
+
+func TestExperimentResults_CompareCommonRandomNumbers(t *testing.T) {
+	factory := func(s Scenario) *Pipeline {
+		pipe := NewPipeline("Barbershop", false)
+		g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+		q := NewQueue("Chairs")
+		f := NewFacility("Master", 16, 4)
+		h := NewHole("Out")
+		pipe.Append(g, q)
+		pipe.Append(q, f)
+		pipe.Append(f, h)
+		pipe.Append(h)
+		return pipe
+	}
+	// Scenarios differ only by parameter which isn't used by model
+	e := NewExperiment(factory, 480, 4)
+	e.SetGrid("Chairs", 1, 2)
+	res := e.Run()
+	c, err := res.Compare(0, 1, "Chairs", "ave_time", 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Paired.Diff != 0 || c.Paired.HalfWidth != 0 {
+		t.Error("Paired-t of the same runs, expected 0 +- 0, got", c.Paired)
+	}
+	if c.Welch.HalfWidth == 0 {
+		t.Error("Welch, expected variance between replications, got", c.Welch)
+	}
+}
@@ -0,0 +1,360 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Function for building new pipeline of model with appended objects
+type ModelFactory func() *Pipeline
+
+// ControlServer is HTTP handler for loading and controlling simulations of
+// registered models. All requests and responses are in JSON. Routes:
+//
+//	GET    /models                         names of registered models
+//	GET    /simulations                    list of simulations
+//	POST   /simulations                    load model {"model": "name"}
+//	GET    /simulations/{id}               state of simulation
+//	DELETE /simulations/{id}               stop and remove simulation
+//	POST   /simulations/{id}/start         start {"sim_time": 1000, "seed": 1}
+//	POST   /simulations/{id}/pause         pause simulation
+//	POST   /simulations/{id}/resume        resume simulation
+//	POST   /simulations/{id}/stop          stop simulation
+//	GET    /simulations/{id}/report        standard report
+//	GET    /simulations/{id}/blocks/{name} state and transacts of object
+//	POST   /simulations/{id}/inject        inject transact
+//	                                       {"block": "name", "parameters": {"A": 1}}
+type ControlServer struct {
+	mu          sync.Mutex
+	factories   map[string]ModelFactory
+	simulations map[int]*simulation
+	id          int // ID of last loaded simulation
+	mux         *http.ServeMux
+}
+
+// Loaded model
+type simulation struct {
+	id      int
+	model   string
+	pipe    *Pipeline
+	started bool
+}
+
+// Information about simulation
+type SimulationInfo struct {
+	ID      int            `json:"id"`
+	Model   string         `json:"model"`
+	Started bool           `json:"started"`
+	State   *PipelineState `json:"state"`
+}
+
+// State of object with transacts in it
+type BlockInfo struct {
+	BlockState
+	Transacts []TransactInfo `json:"transacts"`
+}
+
+// Request for starting simulation
+type StartRequest struct {
+	SimTime int    `json:"sim_time"`
+	Seed    *int64 `json:"seed,omitempty"` // Seed of random streams, random if it is not set
+}
+
+// Request for injecting transact
+type InjectRequest struct {
+	Block      string                 `json:"block"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+type controlError struct {
+	Error string `json:"error"`
+}
+
+// Creates new ControlServer.
+func NewControlServer() *ControlServer {
+	c := &ControlServer{
+		factories:   make(map[string]ModelFactory),
+		simulations: make(map[int]*simulation),
+		mux:         http.NewServeMux(),
+	}
+	c.mux.HandleFunc("/models", allowMethod(http.MethodGet, c.handleModels))
+	c.mux.HandleFunc("/simulations", c.handleSimulations)
+	c.mux.HandleFunc("/simulations/", c.handleSimulation)
+	return c
+}
+
+// Register factory of model with name, registered factory with same name is
+// replaced
+func (c *ControlServer) Register(name string, factory ModelFactory) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.factories[name] = factory
+}
+
+// Load model by name, returns ID of simulation
+func (c *ControlServer) Load(model string) (int, error) {
+	c.mu.Lock()
+	factory, ok := c.factories[model]
+	c.mu.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("model %q is not registered", model)
+	}
+	pipe := factory()
+	if pipe == nil {
+		return 0, fmt.Errorf("model %q is not built", model)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.id++
+	c.simulations[c.id] = &simulation{id: c.id, model: model, pipe: pipe}
+	return c.id, nil
+}
+
+// Get pipeline of simulation by ID, nil if simulation is not found
+func (c *ControlServer) GetPipeline(id int) *Pipeline {
+	if sim := c.getSimulation(id); sim != nil {
+		return sim.pipe
+	}
+	return nil
+}
+
+func (c *ControlServer) getSimulation(id int) *simulation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.simulations[id]
+}
+
+// Serve control API on address, for example "localhost:8080". It blocks.
+func (c *ControlServer) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, c)
+}
+
+func (c *ControlServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mux.ServeHTTP(w, r)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, controlError{Error: err.Error()})
+}
+
+func (c *ControlServer) getInfo(sim *simulation) SimulationInfo {
+	c.mu.Lock()
+	started := sim.started
+	c.mu.Unlock()
+	return SimulationInfo{ID: sim.id, Model: sim.model, Started: started, State: sim.pipe.GetState()}
+}
+
+func (c *ControlServer) handleModels(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	names := make([]string, 0, len(c.factories))
+	for k := range c.factories {
+		names = append(names, k)
+	}
+	c.mu.Unlock()
+	sort.Strings(names)
+	writeJSON(w, http.StatusOK, names)
+}
+
+func (c *ControlServer) handleSimulations(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		c.mu.Lock()
+		sims := make([]*simulation, 0, len(c.simulations))
+		for _, s := range c.simulations {
+			sims = append(sims, s)
+		}
+		c.mu.Unlock()
+		sort.Slice(sims, func(i, j int) bool { return sims[i].id < sims[j].id })
+		infos := make([]SimulationInfo, 0, len(sims))
+		for _, s := range sims {
+			infos = append(infos, c.getInfo(s))
+		}
+		writeJSON(w, http.StatusOK, infos)
+	case http.MethodPost:
+		var req struct {
+			Model string `json:"model"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		id, err := c.Load(req.Model)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, c.getInfo(c.getSimulation(id)))
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+	}
+}
+
+func (c *ControlServer) handleSimulation(w http.ResponseWriter, r *http.Request) {
+	// Path is /simulations/{id}[/action[/name]]
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/simulations/"), "/", 3)
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("simulation %q not found", parts[0]))
+		return
+	}
+	sim := c.getSimulation(id)
+	if sim == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("simulation %d not found", id))
+		return
+	}
+	action := ""
+	if len(parts) > 1 {
+		action = parts[1]
+	}
+	var hndl http.HandlerFunc
+	switch action {
+	case "":
+		if r.Method == http.MethodDelete {
+			c.handleDelete(w, sim)
+			return
+		}
+		hndl = allowMethod(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, c.getInfo(sim))
+		})
+	case "start":
+		hndl = allowMethod(http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			c.handleStart(w, r, sim)
+		})
+	case "pause":
+		hndl = allowMethod(http.MethodPost, c.handleControl(sim, sim.pipe.Pause))
+	case "resume":
+		hndl = allowMethod(http.MethodPost, c.handleControl(sim, sim.pipe.Resume))
+	case "stop":
+		hndl = allowMethod(http.MethodPost, c.handleControl(sim, sim.pipe.Stop))
+	case "report":
+		hndl = allowMethod(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+			sim.pipe.mu.Lock()
+			defer sim.pipe.mu.Unlock()
+			writeJSON(w, http.StatusOK, sim.pipe.GetStandardReport())
+		})
+	case "blocks":
+		if len(parts) < 3 || parts[2] == "" {
+			writeError(w, http.StatusNotFound, fmt.Errorf("name of block is required"))
+			return
+		}
+		hndl = allowMethod(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+			c.handleBlock(w, sim, parts[2])
+		})
+	case "inject":
+		hndl = allowMethod(http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			c.handleInject(w, r, sim)
+		})
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown action %q", action))
+		return
+	}
+	hndl(w, r)
+}
+
+func (c *ControlServer) handleControl(sim *simulation, control func()) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		control()
+		writeJSON(w, http.StatusOK, c.getInfo(sim))
+	}
+}
+
+func (c *ControlServer) handleDelete(w http.ResponseWriter, sim *simulation) {
+	sim.pipe.Stop()
+	c.mu.Lock()
+	delete(c.simulations, sim.id)
+	c.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *ControlServer) handleStart(w http.ResponseWriter, r *http.Request, sim *simulation) {
+	var req StartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.SimTime <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("sim_time must be positive"))
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if sim.started || sim.pipe.IsStopped() {
+		writeError(w, http.StatusConflict, fmt.Errorf("simulation %d is already started", sim.id))
+		return
+	}
+	if req.Seed != nil {
+		sim.pipe.SetSeed(*req.Seed)
+	}
+	if err := sim.pipe.Start(req.SimTime); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	sim.started = true
+	writeJSON(w, http.StatusOK, SimulationInfo{
+		ID: sim.id, Model: sim.model, Started: true, State: sim.pipe.GetState()})
+}
+
+func (c *ControlServer) handleBlock(w http.ResponseWriter, sim *simulation, name string) {
+	p := sim.pipe
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	obj := p.GetObjByName(name)
+	if obj == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("block %q not found", name))
+		return
+	}
+	transacts := p.GetTransactsInObj(name)
+	if transacts == nil {
+		transacts = []TransactInfo{}
+	}
+	writeJSON(w, http.StatusOK, BlockInfo{
+		BlockState: p.getBlockState(obj),
+		Transacts:  transacts,
+	})
+}
+
+func (c *ControlServer) handleInject(w http.ResponseWriter, r *http.Request, sim *simulation) {
+	var req InjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if sim.pipe.GetObjByName(req.Block) == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("block %q not found", req.Block))
+		return
+	}
+	params := make([]Parameter, 0, len(req.Parameters))
+	for k, v := range req.Parameters {
+		params = append(params, Parameter{Name: k, Value: fromJSON(v)})
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	t, err := sim.pipe.Inject(req.Block, params...)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	sim.pipe.mu.Lock()
+	info := newTransactInfo(t)
+	sim.pipe.mu.Unlock()
+	writeJSON(w, http.StatusOK, info)
+}
+
+// Convert integer numbers decoded from JSON to int, handlers of objects
+// usually expect int values of parameters
+func fromJSON(v interface{}) interface{} {
+	if f, ok := v.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int(f)
+	}
+	return v
+}
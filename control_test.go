@@ -0,0 +1,108 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestControlServer(t *testing.T) {
+	control := NewControlServer()
+	control.Register("barbershop", func() *Pipeline {
+		pipe := NewPipeline("Barbershop", false)
+		g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+		q := NewQueue("Chairs")
+		f := NewFacility("Master", 16, 4)
+		h := NewHole("Out")
+		pipe.Append(g, q)
+		pipe.Append(q, f)
+		pipe.Append(f, h)
+		pipe.Append(h)
+		return pipe
+	})
+	server := httptest.NewServer(control)
+	defer server.Close()
+
+	do := func(method, path string, body interface{}, status int, result interface{}) {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		req, _ := http.NewRequest(method, server.URL+path, &buf)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Fatal(method, path, "status, expected", status, "got", resp.StatusCode)
+		}
+		if result != nil {
+			if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var models []string
+	do(http.MethodGet, "/models", nil, http.StatusOK, &models)
+	if len(models) != 1 || models[0] != "barbershop" {
+		t.Error("Models, expected [barbershop], got", models)
+	}
+	do(http.MethodPost, "/simulations", map[string]string{"model": "unknown"}, http.StatusNotFound, nil)
+
+	info := SimulationInfo{}
+	do(http.MethodPost, "/simulations", map[string]string{"model": "barbershop"}, http.StatusCreated, &info)
+	if info.ID != 1 || info.Started || info.State.Name != "Barbershop" {
+		t.Error("Loaded simulation, expected 1 not started, got", info)
+	}
+
+	transact := TransactInfo{}
+	do(http.MethodPost, "/simulations/1/inject",
+		InjectRequest{Block: "Chairs", Parameters: map[string]interface{}{"VIP": 1}},
+		http.StatusOK, &transact)
+	if transact.Parameters["VIP"] != float64(1) {
+		t.Error("Injected transact, expected parameter VIP=1, got", transact.Parameters)
+	}
+	if tr := control.GetPipeline(1).GetTransactByID(transact.ID); tr.GetParameterByName("VIP") != 1 {
+		t.Error("Parameter of transact, expected int 1, got", tr.GetParameterByName("VIP"))
+	}
+	do(http.MethodPost, "/simulations/1/inject", InjectRequest{Block: "Nowhere"}, http.StatusNotFound, nil)
+
+	seed := int64(7)
+	do(http.MethodPost, "/simulations/1/start", StartRequest{SimTime: 480, Seed: &seed}, http.StatusOK, &info)
+	if !info.Started || control.GetPipeline(1).GetSeed() != seed {
+		t.Error("Started simulation, expected seed", seed, "got", control.GetPipeline(1).GetSeed())
+	}
+	do(http.MethodPost, "/simulations/1/start", StartRequest{SimTime: 480}, http.StatusConflict, nil)
+	<-control.GetPipeline(1).Done
+
+	block := BlockInfo{}
+	do(http.MethodGet, "/simulations/1/blocks/"+url.PathEscape("Out"), nil, http.StatusOK, &block)
+	if block.Type != "TERMINATE" || block.Entries == 0 {
+		t.Error("Block Out, expected TERMINATE with entries, got", block)
+	}
+	do(http.MethodGet, "/simulations/1/blocks/Nowhere", nil, http.StatusNotFound, nil)
+
+	report := StandardReport{}
+	do(http.MethodGet, "/simulations/1/report", nil, http.StatusOK, &report)
+	if report.EndTime != 480 || len(report.Facilities) != 1 {
+		t.Error("Report, expected end time 480 and 1 facility, got", report.EndTime, len(report.Facilities))
+	}
+
+	do(http.MethodGet, "/simulations/1/stop", nil, http.StatusMethodNotAllowed, nil)
+	do(http.MethodDelete, "/simulations/1", nil, http.StatusNoContent, nil)
+	do(http.MethodGet, "/simulations/1", nil, http.StatusNotFound, nil)
+	var infos []SimulationInfo
+	do(http.MethodGet, "/simulations", nil, http.StatusOK, &infos)
+	if len(infos) != 0 {
+		t.Error("Simulations, expected", 0, "got", len(infos))
+	}
+}
@@ -12,8 +12,9 @@ import (
 // first for increment Count value, second for decrement Count value
 type Count struct {
 	BaseObj
-	value   *int // Value of counter
-	inc_dec int  // Value of increment/decrement
+	value   *int          // Value of counter
+	inc_dec int           // Value of increment/decrement
+	stat    *TimeWeighted // Time-weighted statistics of value
 }
 
 // Creates two objects, for incremet and decrement. After enter transact in
@@ -29,21 +30,53 @@ func NewCount(name string, inc_value, dec_value int) (*Count, *Count) {
 	dec.name = name + "_DEC"
 	dec.value = inc.value
 	dec.inc_dec = dec_value
+	inc.stat = NewTimeWeighted(0, 0)
+	dec.stat = inc.stat
 	return inc, dec
 }
 
 func (obj *Count) AppendTransact(transact ITransaction) bool {
-	transact.PrintInfo()
-	for _, v := range obj.GetDst() {
-		if v.AppendTransact(transact) {
-			*obj.value += obj.inc_dec
-			obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Count")
-			return true
+	return journalPass(obj.name, transact, func() bool {
+		for _, v := range obj.GetDst() {
+			if SendTransact(obj, v, transact) {
+				*obj.value += obj.inc_dec
+				obj.stat.Add(obj.GetPipeline().GetModelTime(), float64(obj.inc_dec))
+				return true
+			}
 		}
-	}
-	return false
+		return false
+	})
+}
+
+// Get value of counter
+func (obj *Count) GetValue() int {
+	return *obj.value
+}
+
+// Get time-weighted statistics of value of counter
+func (obj *Count) GetValueStat() *TimeWeighted {
+	return obj.stat
 }
 
 func (obj *Count) PrintReport() {
-	fmt.Printf("Count value %d\n", obj.value)
+	fmt.Printf("Count value %d\n", *obj.value)
+}
+
+type countState struct {
+	Value int
+	Stat  timeWeightedState
+}
+
+func (obj *Count) SaveState() ([]byte, error) {
+	return encodeState(countState{Value: *obj.value, Stat: obj.stat.getState()})
+}
+
+func (obj *Count) LoadState(data []byte) error {
+	state := countState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	*obj.value = state.Value
+	obj.stat.setState(state.Stat)
+	return nil
 }
@@ -0,0 +1,83 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"sync/atomic"
+)
+
+// Standard numerical attributes of block
+type BlockCounters struct {
+	Entries  int // Count of transacts entered block (generated for Generator)
+	Current  int // Count of transacts in block now
+	Refusals int // Count of refusals to accept transact
+}
+
+// Counters of block, maintained by pipeline while sending of transacts
+type blockCounters struct {
+	entries  atomic.Int64
+	current  atomic.Int64
+	refusals atomic.Int64
+}
+
+// Get counters of object by name, creates counters if they don't exist.
+// Must be called only while building of pipeline.
+func (p *Pipeline) getCounters(name string) *blockCounters {
+	if p.counters == nil {
+		p.counters = make(map[string]*blockCounters)
+	}
+	c, ok := p.counters[name]
+	if !ok {
+		c = &blockCounters{}
+		p.counters[name] = c
+	}
+	return c
+}
+
+// Get entry count, current count and count of refusals of object by name
+func (p *Pipeline) GetBlockCounters(name string) BlockCounters {
+	var counters BlockCounters
+	if c, ok := p.counters[name]; ok {
+		counters.Entries = int(c.entries.Load())
+		counters.Current = int(c.current.Load())
+		counters.Refusals = int(c.refusals.Load())
+	}
+	return counters
+}
+
+// Add to entry count and current count of object by name
+func (p *Pipeline) addCounters(name string, entries, current int) {
+	if c, ok := p.counters[name]; ok {
+		c.entries.Add(int64(entries))
+		c.current.Add(int64(current))
+	}
+}
+
+// Add to entry count and current count of object, for objects which create
+// or delete transacts, for example Hole kills transacts
+func addCounters(obj IBaseObj, entries, current int) {
+	if r, ok := obj.GetPipeline().(transactRouter); ok {
+		r.addCounters(obj.GetName(), entries, current)
+	}
+}
+
+// Get counters of all objects for saving in checkpoint
+func (p *Pipeline) saveCounters() map[string]BlockCounters {
+	counters := make(map[string]BlockCounters, len(p.counters))
+	for name, c := range p.counters {
+		counters[name] = BlockCounters{Entries: int(c.entries.Load()), Current: int(c.current.Load()),
+			Refusals: int(c.refusals.Load())}
+	}
+	return counters
+}
+
+// Restore counters of objects from checkpoint
+func (p *Pipeline) loadCounters(counters map[string]BlockCounters) {
+	for name, c := range p.counters {
+		c.entries.Store(int64(counters[name].Entries))
+		c.current.Store(int64(counters[name].Current))
+		c.refusals.Store(int64(counters[name].Refusals))
+	}
+}
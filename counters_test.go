@@ -0,0 +1,103 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func newCountersPipeline() *Pipeline {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 5, 0, 0, 0, nil)
+	assign := NewAssign("Assign")
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 20, 0)
+	h := NewHole("Out")
+	pipe.Append(g, assign)
+	pipe.Append(assign, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	return pipe
+}
+
+func TestPipeline_GetBlockCounters(t *testing.T) {
+	pipe := newCountersPipeline()
+	pipe.SetSimTime(100)
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+
+	generated := pipe.GetBlockCounters("Clients").Entries
+	if generated == 0 {
+		t.Error("Entries of Clients, expected not 0, got", generated)
+	}
+	if c := pipe.GetBlockCounters("Assign"); c.Entries != generated || c.Current != 0 {
+		t.Error("Assign, expected", generated, "entries and 0 current, got", c)
+	}
+	// Facility serves slower than clients come, so it refuses transacts
+	if c := pipe.GetBlockCounters("Master"); c.Refusals == 0 || c.Current != 1 {
+		t.Error("Master, expected refusals and 1 current, got", c)
+	}
+	if c := pipe.GetBlockCounters("Chairs"); c.Current != len(pipe.GetTransactsInObj("Chairs")) {
+		t.Error("Current of Chairs, expected", len(pipe.GetTransactsInObj("Chairs")), "got", c.Current)
+	}
+
+	var buf bytes.Buffer
+	if err := pipe.SaveCheckpoint(&buf); err != nil {
+		t.Fatal(err)
+	}
+	restored := newCountersPipeline()
+	if err := restored.LoadCheckpoint(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Clients", "Assign", "Chairs", "Master", "Out"} {
+		if a, b := pipe.GetBlockCounters(name), restored.GetBlockCounters(name); a != b {
+			t.Error("Counters of", name, "after loading, expected", a, "got", b)
+		}
+	}
+}
+
+func TestPipeline_GetBlockCounters_Current(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Orders", 4, 2, 0, 0, nil)
+	split := NewSplit("Split", 2, 0, nil)
+	q1, q2 := NewQueue("Queue 1"), NewQueue("Queue 2")
+	f1, f2 := NewFacility("Worker 1", 5, 3), NewFacility("Worker 2", 3, 2)
+	aggregate := NewAggregate("Aggregate")
+	in, out := NewBifacility("Packer")
+	a := NewAdvance("Packing", 3, 1)
+	h := NewHole("Out")
+	pipe.Append(g, split)
+	pipe.Append(split, q1, q2)
+	pipe.Append(q1, f1)
+	pipe.Append(q2, f2)
+	pipe.Append(f1, aggregate)
+	pipe.Append(f2, aggregate)
+	pipe.Append(aggregate, in)
+	pipe.Append(in, a)
+	pipe.Append(a, out)
+	pipe.Append(out, h)
+	pipe.Append(h)
+	pipe.SetSeed(1)
+	pipe.SetSimTime(200)
+	for !pipe.IsStopped() {
+		pipe.Step()
+		for _, o := range pipe.GetSortedObjects() {
+			expected := len(pipe.GetTransactsInObj(o.GetName()))
+			if c := pipe.GetBlockCounters(o.GetName()); c.Current != expected {
+				t.Fatal("Current of", o.GetName(), "at", pipe.GetModelTime(), "expected", expected, "got", c.Current)
+			}
+		}
+	}
+	if pipe.GetBlockCounters("Out").Entries == 0 {
+		t.Error("Entries of Out, expected not 0")
+	}
+	// Destinations are objects appended to pipeline
+	if dst := split.GetDst(); dst[0] != q1 || dst[1] != q2 {
+		t.Error("Destinations of Split, expected", q1, q2, "got", dst)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Dashboard is HTTP handler which shows state of running pipeline. Routes:
+//
+//	GET  /            page with state, updated by server-sent events
+//	GET  /api/state   state of pipeline in JSON
+//	GET  /api/events  stream of states (server-sent events)
+//	POST /api/pause   pause simulation
+//	POST /api/resume  resume simulation
+//	POST /api/stop    stop simulation
+type Dashboard struct {
+	pipe     *Pipeline
+	interval time.Duration // Interval between events
+	mux      *http.ServeMux
+}
+
+// Creates new Dashboard.
+// pipe - pipeline for watching; interval - interval between updates of state
+func NewDashboard(pipe *Pipeline, interval time.Duration) *Dashboard {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	d := &Dashboard{pipe: pipe, interval: interval, mux: http.NewServeMux()}
+	// Methods are checked by handlers, patterns with methods depend on
+	// version of Go in main module
+	d.mux.HandleFunc("/", allowMethod(http.MethodGet, d.handlePage))
+	d.mux.HandleFunc("/api/state", allowMethod(http.MethodGet, d.handleState))
+	d.mux.HandleFunc("/api/events", allowMethod(http.MethodGet, d.handleEvents))
+	d.mux.HandleFunc("/api/pause", allowMethod(http.MethodPost, d.handleControl(pipe.Pause)))
+	d.mux.HandleFunc("/api/resume", allowMethod(http.MethodPost, d.handleControl(pipe.Resume)))
+	d.mux.HandleFunc("/api/stop", allowMethod(http.MethodPost, d.handleControl(pipe.Stop)))
+	return d
+}
+
+// Handle only requests with method, responds 405 to others
+func allowMethod(method string, hndl http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		hndl(w, r)
+	}
+}
+
+// Serve dashboard on address, for example "localhost:8080". It blocks, so
+// it is usually started in goroutine before Start of pipeline.
+func (d *Dashboard) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, d)
+}
+
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (d *Dashboard) handleState(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, d.pipe.GetState())
+}
+
+func (d *Dashboard) handleControl(control func()) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		control()
+		writeJSON(w, http.StatusOK, d.pipe.GetState())
+	}
+}
+
+func (d *Dashboard) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		state := d.pipe.GetState()
+		data, err := json.Marshal(state)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+		if state.Stopped {
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dashboard) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, dashboardPage)
+}
+
+const dashboardPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Pipeline</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th { background: #eef3f8; }
+td:first-child, th:first-child { text-align: left; }
+#stall { background: #fff3cd; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1 id="name">Pipeline</h1>
+<p>Model time <b id="time"></b> of <span id="simtime"></span>, <span id="status"></span></p>
+<p>
+<button onclick="control('pause')">Pause</button>
+<button onclick="control('resume')">Resume</button>
+<button onclick="control('stop')">Stop</button>
+</p>
+<div id="stall"></div>
+<table>
+<thead><tr><th>Name</th><th>Type</th><th>Entries</th><th>Current</th><th>Refusals</th><th>State</th></tr></thead>
+<tbody id="blocks"></tbody>
+</table>
+<script>
+function cell(row, text) {
+	const td = document.createElement("td");
+	td.textContent = text;
+	row.appendChild(td);
+}
+function render(state) {
+	document.getElementById("name").textContent = state.name;
+	document.getElementById("time").textContent = state.model_time;
+	document.getElementById("simtime").textContent = state.sim_time;
+	document.getElementById("status").textContent =
+		state.stopped ? "stopped" : state.paused ? "paused" : "running";
+	document.getElementById("stall").textContent = state.stall || "";
+	const body = document.getElementById("blocks");
+	body.textContent = "";
+	for (const b of state.blocks) {
+		const row = document.createElement("tr");
+		let info = "";
+		if (b.length !== undefined) info = "length " + b.length;
+		if (b.busy !== undefined) info = b.busy ? "busy by " + b.owner : "empty";
+		if (b.value !== undefined) info = "value " + b.value;
+		for (const v of [b.name, b.type, b.entries, b.current, b.refusals, info]) cell(row, v);
+		body.appendChild(row);
+	}
+}
+function control(action) {
+	fetch("api/" + action, {method: "POST"}).then(r => r.json()).then(render);
+}
+const events = new EventSource("api/events");
+events.onmessage = e => {
+	const state = JSON.parse(e.data);
+	render(state);
+	if (state.stopped) events.close();
+};
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,81 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboard(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 5, 0, 0, 0, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 7, 0)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	server := httptest.NewServer(NewDashboard(pipe, 10*time.Millisecond))
+	defer server.Close()
+
+	post := func(action string) *PipelineState {
+		resp, err := http.Post(server.URL+"/api/"+action, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		state := &PipelineState{}
+		if err := json.NewDecoder(resp.Body).Decode(state); err != nil {
+			t.Fatal(err)
+		}
+		return state
+	}
+
+	if err := pipe.Start(1000000000); err != nil {
+		t.Fatal(err)
+	}
+	paused := post("pause")
+	time.Sleep(20 * time.Millisecond)
+	if state := pipe.GetState(); !state.Paused || state.ModelTime > paused.ModelTime+1 {
+		t.Error("Paused state, expected model time", paused.ModelTime, "got", state)
+	}
+	if state := post("resume"); state.Paused {
+		t.Error("Resumed state, expected not paused, got", state)
+	}
+
+	resp, err := http.Get(server.URL + "/api/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Error("Content type, expected text/event-stream, got", ct)
+	}
+	post("stop")
+	// Stream ends after event with stopped pipeline
+	var last PipelineState
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			if err := json.Unmarshal([]byte(data), &last); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if !last.Stopped || len(last.Blocks) != 4 {
+		t.Error("Last event, expected stopped pipeline with 4 blocks, got", last)
+	}
+	if last.Blocks[1].Length == nil || last.Blocks[2].Busy == nil {
+		t.Error("Blocks, expected length of queue and busy state of facility, got", last.Blocks)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Debugger is an interactive shell for step by step simulation of pipeline.
+// Commands are read from in, answers and reports are written to out.
+type Debugger struct {
+	pipe *Pipeline
+	in   io.Reader
+	out  io.Writer
+}
+
+// Creates new Debugger.
+// pipe - pipeline for debugging; in - source of commands; out - destination
+// for answers
+func NewDebugger(pipe *Pipeline, in io.Reader, out io.Writer) *Debugger {
+	return &Debugger{pipe: pipe, in: in, out: out}
+}
+
+// Debug own model in terminal: simulation time is set, problems of pipeline
+// are printed and commands are read from stdin until quit command
+func RunDebugger(pipe *Pipeline, simTime int) error {
+	if simTime <= 0 {
+		return fmt.Errorf("simulation time must be positive, got %d", simTime)
+	}
+	pipe.SetSimTime(simTime)
+	for _, v := range pipe.Validate() {
+		fmt.Println(v)
+	}
+	return NewDebugger(pipe, os.Stdin, os.Stdout).Run()
+}
+
+const debuggerHelp = `Commands:
+  step [n]                      make n steps of simulation (default 1)
+  run-until <time>              run simulation until model time
+  continue                      run simulation until the end
+  show block <name>             show object of pipeline
+  show transact <id>            show transact
+  list blocks                   list all objects of pipeline
+  list queues                   list all queues with its length
+  list transacts                list all live transacts
+  set <id> <parameter> <value>  set parameter of transact
+  report                        print report in GPSS World format
+  validate                      print problems of pipeline
+  help                          show this help
+  quit                          exit from debugger`
+
+// Read commands and execute it until quit command or end of input
+func (d *Debugger) Run() error {
+	scanner := bufio.NewScanner(d.in)
+	d.prompt()
+	for scanner.Scan() {
+		args := strings.Fields(scanner.Text())
+		if len(args) > 0 {
+			if args[0] == "quit" || args[0] == "exit" {
+				return nil
+			}
+			if err := d.Execute(args); err != nil {
+				fmt.Fprintln(d.out, "Error:", err)
+			}
+		}
+		d.prompt()
+	}
+	return scanner.Err()
+}
+
+func (d *Debugger) prompt() {
+	fmt.Fprintf(d.out, "(gpss %d/%d) ", d.pipe.GetModelTime(), d.pipe.GetSimTime())
+}
+
+// Execute one command, args[0] is a name of command
+func (d *Debugger) Execute(args []string) error {
+	switch args[0] {
+	case "help":
+		fmt.Fprintln(d.out, debuggerHelp)
+	case "step":
+		n := 1
+		if len(args) > 1 {
+			var err error
+			if n, err = strconv.Atoi(args[1]); err != nil {
+				return err
+			}
+		}
+		d.step(func(int) bool { n--; return n >= 0 })
+	case "run-until":
+		if len(args) < 2 {
+			return fmt.Errorf("model time is required")
+		}
+		until, err := strconv.Atoi(args[1])
+		if err != nil {
+			return err
+		}
+		d.step(func(modelTime int) bool { return modelTime < until })
+	case "continue":
+		d.step(func(int) bool { return true })
+	case "show":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: show block <name> | show transact <id>")
+		}
+		return d.show(args[1], strings.Join(args[2:], " "))
+	case "list":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: list blocks | list queues | list transacts")
+		}
+		return d.list(args[1])
+	case "set":
+		if len(args) < 4 {
+			return fmt.Errorf("usage: set <id> <parameter> <value>")
+		}
+		return d.set(args[1], args[2], strings.Join(args[3:], " "))
+	case "report":
+		d.pipe.GetStandardReport().Write(d.out)
+	case "validate":
+		for _, v := range d.pipe.Validate() {
+			fmt.Fprintln(d.out, v)
+		}
+	default:
+		return fmt.Errorf("unknown command %q, type help for list of commands", args[0])
+	}
+	return nil
+}
+
+// Write lines of standard report about object
+func (d *Debugger) writeBlockReport(name string) {
+	r := d.pipe.GetStandardReport()
+	report := &StandardReport{Name: r.Name, StartTime: r.StartTime, EndTime: r.EndTime}
+	for _, v := range r.Blocks {
+		if v.Name == name {
+			report.Blocks = append(report.Blocks, v)
+		}
+	}
+	for _, v := range r.Facilities {
+		if v.Name == name {
+			report.Facilities = append(report.Facilities, v)
+		}
+	}
+	for _, v := range r.Queues {
+		if v.Name == name {
+			report.Queues = append(report.Queues, v)
+		}
+	}
+	for _, v := range r.Savevalues {
+		if v.Name == name {
+			report.Savevalues = append(report.Savevalues, v)
+		}
+	}
+	for _, v := range r.Quantiles {
+		if v.Name == name {
+			report.Quantiles = append(report.Quantiles, v)
+		}
+	}
+	report.Write(d.out)
+}
+
+// Make steps of simulation while cond is true
+func (d *Debugger) step(cond func(modelTime int) bool) {
+	for !d.pipe.IsStopped() && cond(d.pipe.GetModelTime()) {
+		d.pipe.Step()
+	}
+	if d.pipe.IsStopped() {
+		fmt.Fprintln(d.out, "Simulation is finished")
+	}
+}
+
+func (d *Debugger) show(what, arg string) error {
+	switch what {
+	case "block":
+		obj := d.pipe.GetObjByName(arg)
+		if obj == nil {
+			return fmt.Errorf("object %q not found", arg)
+		}
+		fmt.Fprintln(d.out, "Name:\t", obj.GetName())
+		fmt.Fprintln(d.out, "ID:\t", obj.GetID())
+		fmt.Fprintln(d.out, "Type:\t", reflect.TypeOf(obj))
+		dst := make([]string, 0, len(obj.GetDst()))
+		for _, v := range obj.GetDst() {
+			dst = append(dst, strconv.Quote(v.GetName()))
+		}
+		fmt.Fprintln(d.out, "Dst:\t", strings.Join(dst, ", "))
+		counters := d.pipe.GetBlockCounters(obj.GetName())
+		fmt.Fprintln(d.out, "Entries:\t", counters.Entries)
+		fmt.Fprintln(d.out, "Current:\t", counters.Current)
+		fmt.Fprintln(d.out, "Refusals:\t", counters.Refusals)
+		if tabler, ok := obj.(ITransactTabler); ok && tabler.GetTransactTable() != nil {
+			items := tabler.GetTransactTable().GetItems()
+			ids := make([]int, 0, len(items))
+			for id := range items {
+				ids = append(ids, id)
+			}
+			sort.Ints(ids)
+			fmt.Fprintln(d.out, "Transacts:\t", ids)
+		}
+		d.writeBlockReport(obj.GetName())
+	case "transact":
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return err
+		}
+		transact := d.pipe.GetTransactByID(id)
+		if transact == nil {
+			return fmt.Errorf("transact %d not found", id)
+		}
+		d.printTransact(transact)
+	default:
+		return fmt.Errorf("unknown object type %q", what)
+	}
+	return nil
+}
+
+func (d *Debugger) printTransact(transact ITransaction) {
+	part, parts, parent_id := transact.GetParts()
+	fmt.Fprintln(d.out, "ID:\t\t", transact.GetId())
+	fmt.Fprintln(d.out, "Holder name:\t", transact.GetHolderName())
+	fmt.Fprintln(d.out, "Time in holder:\t", transact.GetTimeInHolder())
+	fmt.Fprintln(d.out, "Killed:\t\t", transact.IsKilled())
+	fmt.Fprintln(d.out, "Ticks:\t\t", transact.GetTicks())
+	fmt.Fprintln(d.out, "Time in queue:\t", transact.GetQueueTime())
+	fmt.Fprintln(d.out, "Advance time:\t", transact.GetAdvanceTime())
+	fmt.Fprintf(d.out, "Parts:\t\t %d/%d, parent %d\n", part, parts, parent_id)
+	parameters := transact.GetAllParameters()
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(d.out, "Parameter %q:\t %v\n", name, parameters[name])
+	}
+}
+
+func (d *Debugger) list(what string) error {
+	switch what {
+	case "blocks":
+		for _, obj := range d.pipe.GetSortedObjects() {
+			fmt.Fprintf(d.out, "%d\t%s\t%q\n", obj.GetID(), reflect.TypeOf(obj), obj.GetName())
+		}
+	case "queues":
+		for _, obj := range d.pipe.GetSortedObjects() {
+			if queue, ok := obj.(IQueue); ok {
+				fmt.Fprintf(d.out, "%q\tlength %d\n", obj.GetName(), queue.GetLength())
+			}
+		}
+	case "transacts":
+		for _, info := range d.pipe.GetTransacts() {
+			fmt.Fprintf(d.out, "%d\t%q\tin holder %d\tin queue %d\n", info.ID, info.HolderName,
+				info.TimeInHolder, info.QueueTime)
+		}
+	default:
+		return fmt.Errorf("unknown list %q", what)
+	}
+	return nil
+}
+
+// Set parameter of transact, value is converted to int if it possible
+func (d *Debugger) set(idStr, name, valueStr string) error {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return err
+	}
+	transact := d.pipe.GetTransactByID(id)
+	if transact == nil {
+		return fmt.Errorf("transact %d not found", id)
+	}
+	var value interface{} = valueStr
+	if i, err := strconv.Atoi(valueStr); err == nil {
+		value = i
+	}
+	transact.SetParameters([]Parameter{{Name: name, Value: value}})
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newDebuggerPipeline() *Pipeline {
+	pipe := NewPipeline("Barbershop", false)
+	g := NewGenerator("Clients", 18, 0, 0, 0, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 16, 0)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	pipe.SetSimTime(100)
+	return pipe
+}
+
+func TestDebugger_Execute(t *testing.T) {
+	pipe := newDebuggerPipeline()
+	var out bytes.Buffer
+	d := NewDebugger(pipe, nil, &out)
+
+	if err := d.Execute([]string{"step"}); err != nil || pipe.GetModelTime() != 1 {
+		t.Error("Step, expected model time", 1, "got", pipe.GetModelTime(), err)
+	}
+	if err := d.Execute([]string{"step", "4"}); err != nil || pipe.GetModelTime() != 5 {
+		t.Error("Step 4, expected model time", 5, "got", pipe.GetModelTime(), err)
+	}
+	// Break at model time
+	if err := d.Execute([]string{"run-until", "40"}); err != nil || pipe.GetModelTime() != 40 {
+		t.Error("Run until 40, expected model time", 40, "got", pipe.GetModelTime(), err)
+	}
+	holded := pipe.GetObjByName("Master").(*Facility).HoldedTransactID
+	if holded <= 0 {
+		t.Fatal("Master at 40, expected busy, got", holded)
+	}
+	id := strconv.Itoa(holded)
+	if err := d.Execute([]string{"set", id, "Vip", "1"}); err != nil {
+		t.Error("Set parameter, expected nil, got", err)
+	}
+	out.Reset()
+	if err := d.Execute([]string{"show", "transact", id}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "ID:\t\t "+id+"\n") ||
+		!strings.Contains(out.String(), "Parameter \"Vip\":\t 1\n") {
+		t.Error("Show transact, expected transact", id, "with parameter Vip, got", out.String())
+	}
+	out.Reset()
+	if err := d.Execute([]string{"list", "queues"}); err != nil || !strings.Contains(out.String(), "\"Chairs\"\tlength") {
+		t.Error("List queues, expected Chairs, got", out.String(), err)
+	}
+	out.Reset()
+	if err := d.Execute([]string{"report"}); err != nil || !strings.Contains(out.String(), "GPSS World Simulation Report") {
+		t.Error("Report, expected standard report in output, got", out.String(), err)
+	}
+	out.Reset()
+	if err := d.Execute([]string{"continue"}); err != nil || !pipe.IsStopped() || pipe.GetModelTime() != 100 {
+		t.Error("Continue, expected stopped at", 100, "got", pipe.GetModelTime(), err)
+	}
+	if !strings.Contains(out.String(), "Simulation is finished") {
+		t.Error("Continue, expected end of simulation, got", out.String())
+	}
+
+	for _, args := range [][]string{{"unknown"}, {"step", "x"}, {"show", "block", "Unknown"}, {"run-until"}} {
+		if err := d.Execute(args); err == nil {
+			t.Error("Command", args, "expected error, got nil")
+		}
+	}
+}
+
+func TestDebugger_Run(t *testing.T) {
+	pipe := newDebuggerPipeline()
+	var out bytes.Buffer
+	in := strings.NewReader("step 10\nshow block Chairs\nbad\nquit\nstep\n")
+	if err := NewDebugger(pipe, in, &out).Run(); err != nil {
+		t.Fatal(err)
+	}
+	// Commands after quit aren't executed
+	if pipe.GetModelTime() != 10 {
+		t.Error("Model time, expected", 10, "got", pipe.GetModelTime())
+	}
+	for _, s := range []string{"(gpss 0/100) ", "(gpss 10/100) ", "Name:\t Chairs\n", " QUEUE ",
+		"Error: unknown command \"bad\""} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("Output, expected %q, got %q", s, out.String())
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+)
+
+// Size of objects on diagram
+const (
+	diagramBoxWidth  = 150
+	diagramBoxHeight = 40
+	diagramColumn    = 170
+	diagramRow       = 80
+	diagramMargin    = 20
+)
+
+// Write diagram of pipeline in SVG format. Objects are placed in rows by
+// distance from generators, arrows show destinations, destination of Check in
+// case false result of checking is dashed.
+func (p *Pipeline) WriteDiagramSVG(w io.Writer) error {
+	objects := p.GetSortedObjects()
+	depth := make(map[IBaseObj]int)
+	var queue []IBaseObj
+	for _, o := range objects {
+		if _, ok := o.(*Generator); ok {
+			depth[o] = 0
+			queue = append(queue, o)
+		}
+	}
+	for len(queue) > 0 {
+		o := queue[0]
+		queue = queue[1:]
+		for _, d := range getAllDst(o) {
+			if _, ok := depth[d]; !ok {
+				depth[d] = depth[o] + 1
+				queue = append(queue, d)
+			}
+		}
+	}
+	// Unreachable objects are placed in the last row
+	maxDepth := 0
+	for _, d := range depth {
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	for _, o := range objects {
+		if _, ok := depth[o]; !ok {
+			depth[o] = maxDepth + 1
+		}
+	}
+
+	type position struct{ x, y int }
+	positions := make(map[IBaseObj]position)
+	rows := make(map[int]int)
+	columns, lastRow := 1, 0
+	for _, o := range objects {
+		row := depth[o]
+		positions[o] = position{diagramMargin + rows[row]*diagramColumn, diagramMargin + row*diagramRow}
+		rows[row]++
+		if rows[row] > columns {
+			columns = rows[row]
+		}
+		if row > lastRow {
+			lastRow = row
+		}
+	}
+	width := 2*diagramMargin + (columns-1)*diagramColumn + diagramBoxWidth
+	height := 2*diagramMargin + lastRow*diagramRow + diagramBoxHeight
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n",
+		width, height, width, height)
+	fmt.Fprintln(bw, `<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0 L10,5 L0,10 z"/></marker></defs>`)
+	for _, o := range objects {
+		from := positions[o]
+		var falseObj IBaseObj
+		if check, ok := o.(*Check); ok && check.falseObj != nil {
+			falseObj = check.falseObj
+		}
+		for _, d := range getAllDst(o) {
+			to, ok := positions[d]
+			if !ok {
+				continue
+			}
+			dash := ""
+			if d == falseObj {
+				dash = ` stroke-dasharray="4,3"`
+			}
+			x1, y1 := from.x+diagramBoxWidth/2, from.y+diagramBoxHeight
+			x2, y2 := to.x+diagramBoxWidth/2, to.y
+			if to.y <= from.y {
+				// Back link goes around right side of objects
+				x1, y1 = from.x+diagramBoxWidth, from.y+diagramBoxHeight/2
+				x2, y2 = to.x+diagramBoxWidth, to.y+diagramBoxHeight/2
+				fmt.Fprintf(bw, `<path d="M%d,%d C%d,%d %d,%d %d,%d" fill="none" stroke="#555"%s marker-end="url(#arrow)"/>`+"\n",
+					x1, y1, x1+40, y1, x2+40, y2, x2, y2, dash)
+				continue
+			}
+			fmt.Fprintf(bw, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#555"%s marker-end="url(#arrow)"/>`+"\n",
+				x1, y1, x2, y2, dash)
+		}
+	}
+	for _, o := range objects {
+		pos := positions[o]
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="#eef3f8" stroke="#4e79a7"/>`+"\n",
+			pos.x, pos.y, diagramBoxWidth, diagramBoxHeight)
+		fmt.Fprintf(bw, `<text x="%d" y="%d" text-anchor="middle" font-weight="bold">%s</text>`+"\n",
+			pos.x+diagramBoxWidth/2, pos.y+16, html.EscapeString(o.GetName()))
+		fmt.Fprintf(bw, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n",
+			pos.x+diagramBoxWidth/2, pos.y+31, getBlockType(o))
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
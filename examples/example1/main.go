@@ -21,7 +21,10 @@ func main() {
 	p.Append(q, f)
 	p.Append(f, h)
 	p.Append(h)
-	p.Start(480)
+	if err := p.Start(480); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	// Comment code before and uncoment next code for test Barbershop with
 	// bifacility
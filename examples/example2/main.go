@@ -26,7 +26,10 @@ func main() {
 	p.Append(f1, a3)
 	p.Append(f2, a3)
 	p.Append(a3, a1)
-	p.Start(540)
+	if err := p.Start(540); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	// Exit handler
 	exit := make(chan struct{})
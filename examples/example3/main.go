@@ -33,7 +33,10 @@ func main() {
 	p.Append(cook_f, aggregate)
 	p.Append(aggregate, h)
 	p.Append(h)
-	p.Start(480)
+	if err := p.Start(480); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	// Exit handler
 	exit := make(chan struct{})
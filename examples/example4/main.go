@@ -147,7 +147,10 @@ func main() {
 	restaurant.AppendISlice(visitors_pays, tables_out)
 	restaurant.AppendMultiple(tables_out, out)
 	restaurant.Append(out)
-	restaurant.Start(480)
+	if err := restaurant.Start(480); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	// Exit handler
 	exit := make(chan struct{})
@@ -0,0 +1,375 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Values of parameters of model, for example interval of Generator or number
+// of waiters
+type Scenario map[string]float64
+
+// Function for building pipeline of model with parameters of scenario
+type ExperimentFactory func(scenario Scenario) *Pipeline
+
+// Experiment runs replications of model for each scenario in parallel.
+// Scenarios are all combinations of values from grid of parameters and
+// scenarios appended by AddScenario. Replication r of each scenario uses same
+// seed, runs with same seed are repeated exactly and each object has own
+// random stream, so scenarios are compared with common random numbers.
+type Experiment struct {
+	factory      ExperimentFactory
+	simTime      int                  // Simulation time of each run
+	replications int                  // Replications for each scenario
+	grid         map[string][]float64 // Values of parameters
+	scenarios    []Scenario           // Scenarios appended by AddScenario
+	seed         int64                // Seed of first replication
+	workers      int                  // Count of parallel runs
+}
+
+// Statistic of object in run of experiment
+type ExperimentRow struct {
+	Scenario    int      `json:"scenario"`    // Index of scenario
+	Parameters  Scenario `json:"parameters"`  // Parameters of scenario
+	Replication int      `json:"replication"` // Index of replication
+	Seed        int64    `json:"seed"`        // Seed of replication
+	Object      string   `json:"object"`      // Name of object
+	Statistic   string   `json:"statistic"`   // Name of statistic
+	Value       float64  `json:"value"`
+}
+
+// Results of experiment, one row for each statistic of each object of each
+// run
+type ExperimentResults struct {
+	Scenarios    []Scenario      `json:"scenarios"`
+	Replications int             `json:"replications"`
+	Rows         []ExperimentRow `json:"rows"`
+	Errors       []string        `json:"errors,omitempty"` // Errors of runs not started
+}
+
+// Summary of statistic of object over replications of scenario
+type ExperimentSummary struct {
+	Scenario   int      `json:"scenario"`
+	Parameters Scenario `json:"parameters"`
+	Object     string   `json:"object"`
+	Statistic  string   `json:"statistic"`
+	Count      int      `json:"count"`
+	Mean       float64  `json:"mean"`
+	StdDev     float64  `json:"std_dev"`
+	Min        float64  `json:"min"`
+	Max        float64  `json:"max"`
+}
+
+// Creates new Experiment.
+// factory - function for building model; simTime - simulation time of each
+// run; replications - count of runs of each scenario
+func NewExperiment(factory ExperimentFactory, simTime, replications int) *Experiment {
+	if replications < 1 {
+		replications = 1
+	}
+	return &Experiment{
+		factory:      factory,
+		simTime:      simTime,
+		replications: replications,
+		grid:         make(map[string][]float64),
+		seed:         1,
+		workers:      runtime.GOMAXPROCS(0),
+	}
+}
+
+// Set values of parameter in grid of scenarios
+func (e *Experiment) SetGrid(name string, values ...float64) {
+	e.grid[name] = values
+}
+
+// Append scenario to list of scenarios
+func (e *Experiment) AddScenario(scenario Scenario) {
+	e.scenarios = append(e.scenarios, scenario)
+}
+
+// Set seed of first replication, replication r uses seed+r
+func (e *Experiment) SetSeed(seed int64) {
+	e.seed = seed
+}
+
+// Set count of parallel runs, by default it is GOMAXPROCS
+func (e *Experiment) SetWorkers(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	e.workers = workers
+}
+
+// Get scenarios of experiment: combinations of grid, then appended scenarios.
+// Experiment without scenarios has one empty scenario.
+func (e *Experiment) GetScenarios() []Scenario {
+	names := make([]string, 0, len(e.grid))
+	for k := range e.grid {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var scenarios []Scenario
+	if len(names) > 0 {
+		scenarios = []Scenario{{}}
+		for _, name := range names {
+			next := make([]Scenario, 0, len(scenarios)*len(e.grid[name]))
+			for _, s := range scenarios {
+				for _, v := range e.grid[name] {
+					n := Scenario{name: v}
+					for k, sv := range s {
+						n[k] = sv
+					}
+					next = append(next, n)
+				}
+			}
+			scenarios = next
+		}
+	}
+	scenarios = append(scenarios, e.scenarios...)
+	if len(scenarios) == 0 {
+		scenarios = []Scenario{{}}
+	}
+	return scenarios
+}
+
+// Run all scenarios and wait results
+func (e *Experiment) Run() *ExperimentResults {
+	scenarios := e.GetScenarios()
+	res := &ExperimentResults{Scenarios: scenarios, Replications: e.replications}
+	type run struct {
+		scenario, replication int
+	}
+	runs := make(chan run)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	rows := make([][]ExperimentRow, len(scenarios)*e.replications)
+	for i := 0; i < e.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range runs {
+				seed := e.seed + int64(r.replication)
+				report, err := e.runOnce(scenarios[r.scenario], seed)
+				if err != nil {
+					mu.Lock()
+					res.Errors = append(res.Errors,
+						fmt.Sprintf("scenario %d, replication %d: %v", r.scenario, r.replication, err))
+					mu.Unlock()
+					continue
+				}
+				var runRows []ExperimentRow
+				for _, s := range report.getStatistics() {
+					runRows = append(runRows, ExperimentRow{
+						Scenario:    r.scenario,
+						Parameters:  scenarios[r.scenario],
+						Replication: r.replication,
+						Seed:        seed,
+						Object:      s.object,
+						Statistic:   s.name,
+						Value:       s.value,
+					})
+				}
+				rows[r.scenario*e.replications+r.replication] = runRows
+			}
+		}()
+	}
+	for s := range scenarios {
+		for r := 0; r < e.replications; r++ {
+			runs <- run{s, r}
+		}
+	}
+	close(runs)
+	wg.Wait()
+	for _, r := range rows {
+		res.Rows = append(res.Rows, r...)
+	}
+	sort.Strings(res.Errors)
+	return res
+}
+
+func (e *Experiment) runOnce(scenario Scenario, seed int64) (*StandardReport, error) {
+	pipe := e.factory(scenario)
+	if pipe == nil {
+		return nil, fmt.Errorf("model is not built")
+	}
+	pipe.SetSeed(seed)
+	if err := pipe.Run(e.simTime); err != nil {
+		return nil, err
+	}
+	return pipe.GetStandardReport(), nil
+}
+
+type statistic struct {
+	object, name string
+	value        float64
+}
+
+// Get statistics of report as flat list
+func (r *StandardReport) getStatistics() []statistic {
+	var stats []statistic
+	add := func(object, name string, value float64) {
+		stats = append(stats, statistic{object, name, value})
+	}
+	for _, b := range r.Blocks {
+		add(b.Name, "entry_count", float64(b.EntryCount))
+		add(b.Name, "current_count", float64(b.CurrentCount))
+		add(b.Name, "refusals", float64(b.Refusals))
+	}
+	for _, f := range r.Facilities {
+		add(f.Name, "entries", float64(f.Entries))
+		add(f.Name, "util", f.Util)
+		add(f.Name, "ave_time", f.AveTime)
+	}
+	for _, q := range r.Queues {
+		add(q.Name, "max", float64(q.Max))
+		add(q.Name, "cont", float64(q.Cont))
+		add(q.Name, "entry", float64(q.Entry))
+		add(q.Name, "entry0", float64(q.Entry0))
+		add(q.Name, "ave_cont", q.AveCont)
+		add(q.Name, "ave_time", q.AveTime)
+		add(q.Name, "ave_time_non_zero", q.AveTimeNonZero)
+	}
+	for _, s := range r.Savevalues {
+		add(s.Name, "value", s.Value)
+	}
+	for _, t := range r.Tables {
+		add(t.Name, "entries", float64(t.Entries))
+		add(t.Name, "mean", t.Mean)
+		add(t.Name, "std_dev", t.StdDev)
+	}
+	for _, q := range r.Quantiles {
+		kind := strings.Replace(q.Kind, " ", "_", -1)
+		add(q.Name, kind+"_p50", q.P50)
+		add(q.Name, kind+"_p90", q.P90)
+		add(q.Name, kind+"_p95", q.P95)
+		add(q.Name, kind+"_p99", q.P99)
+		add(q.Name, kind+"_max", q.Max)
+	}
+	return stats
+}
+
+// Get values of statistic of object in scenario, ordered by replications
+func (res *ExperimentResults) GetValues(scenario int, object, statistic string) []float64 {
+	var values []float64
+	for _, r := range res.Rows {
+		if r.Scenario == scenario && r.Object == object && r.Statistic == statistic {
+			values = append(values, r.Value)
+		}
+	}
+	return values
+}
+
+// Get summary of statistics over replications for each scenario
+func (res *ExperimentResults) GetSummary() []ExperimentSummary {
+	type key struct {
+		scenario          int
+		object, statistic string
+	}
+	var keys []key
+	values := make(map[key][]float64)
+	for _, r := range res.Rows {
+		k := key{r.Scenario, r.Object, r.Statistic}
+		if _, ok := values[k]; !ok {
+			keys = append(keys, k)
+		}
+		values[k] = append(values[k], r.Value)
+	}
+	summary := make([]ExperimentSummary, 0, len(keys))
+	for _, k := range keys {
+		v := values[k]
+		s := ExperimentSummary{Scenario: k.scenario, Parameters: res.Scenarios[k.scenario],
+			Object: k.object, Statistic: k.statistic, Count: len(v), Min: v[0], Max: v[0]}
+		for _, x := range v {
+			s.Mean += x
+			s.Min = math.Min(s.Min, x)
+			s.Max = math.Max(s.Max, x)
+		}
+		s.Mean /= float64(len(v))
+		if len(v) > 1 {
+			for _, x := range v {
+				s.StdDev += (x - s.Mean) * (x - s.Mean)
+			}
+			s.StdDev = math.Sqrt(s.StdDev / float64(len(v)-1))
+		}
+		summary = append(summary, s)
+	}
+	return summary
+}
+
+// Get names of parameters of all scenarios, sorted
+func (res *ExperimentResults) getParameterNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, s := range res.Scenarios {
+		for k := range s {
+			if !seen[k] {
+				seen[k] = true
+				names = append(names, k)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write rows of results in CSV format, with column for each parameter
+func (res *ExperimentResults) WriteCSV(w io.Writer) error {
+	names := res.getParameterNames()
+	cw := csv.NewWriter(w)
+	header := append([]string{"scenario"}, names...)
+	header = append(header, "replication", "seed", "object", "statistic", "value")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range res.Rows {
+		record := []string{strconv.Itoa(r.Scenario)}
+		for _, n := range names {
+			v, ok := r.Parameters[n]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		record = append(record, strconv.Itoa(r.Replication), strconv.FormatInt(r.Seed, 10),
+			r.Object, r.Statistic, strconv.FormatFloat(r.Value, 'g', -1, 64))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Print summary of results
+func (res *ExperimentResults) PrintReport() {
+	names := res.getParameterNames()
+	fmt.Println("Experiment:", len(res.Scenarios), "scenarios,", res.Replications, "replications")
+	for i, s := range res.Scenarios {
+		params := make([]string, 0, len(names))
+		for _, n := range names {
+			if v, ok := s[n]; ok {
+				params = append(params, fmt.Sprintf("%s=%g", n, v))
+			}
+		}
+		fmt.Println("Scenario", i, strings.Join(params, " "))
+	}
+	for _, s := range res.GetSummary() {
+		fmt.Printf("%d\t%s\t%s\tmean %g\tstd dev %g\tmin %g\tmax %g\n",
+			s.Scenario, s.Object, s.Statistic, s.Mean, s.StdDev, s.Min, s.Max)
+	}
+	for _, e := range res.Errors {
+		fmt.Println("Error:", e)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func TestExperiment(t *testing.T) {
+	factory := func(s Scenario) *Pipeline {
+		pipe := NewPipeline("Barbershop", false)
+		g := NewGenerator("Clients", int(s["Interval"]), 6, 0, 0, nil)
+		q := NewQueue("Chairs")
+		f := NewFacility("Master", int(s["Service"]), 4)
+		h := NewHole("Out")
+		pipe.Append(g, q)
+		pipe.Append(q, f)
+		pipe.Append(f, h)
+		pipe.Append(h)
+		return pipe
+	}
+	e := NewExperiment(factory, 480, 3)
+	e.SetGrid("Interval", 18, 30)
+	e.SetGrid("Service", 16)
+	e.AddScenario(Scenario{"Interval": 10, "Service": 16})
+	res := e.Run()
+	if len(res.Errors) != 0 {
+		t.Fatal("Errors, expected none, got", res.Errors)
+	}
+	if len(res.Scenarios) != 3 || res.Scenarios[1]["Interval"] != 30 || res.Scenarios[2]["Interval"] != 10 {
+		t.Fatal("Scenarios, expected Interval 18, 30, 10, got", res.Scenarios)
+	}
+	for s := range res.Scenarios {
+		if values := res.GetValues(s, "Master", "util"); len(values) != 3 {
+			t.Error("Utilization of Master in scenario", s, "expected 3 values, got", values)
+		}
+	}
+	// Overloaded master has longer queue than underloaded
+	summary := make(map[int]ExperimentSummary)
+	for _, s := range res.GetSummary() {
+		if s.Object == "Chairs" && s.Statistic == "ave_cont" {
+			summary[s.Scenario] = s
+		}
+	}
+	if summary[2].Mean <= summary[1].Mean {
+		t.Error("Average content of Chairs, expected more for Interval=10, got",
+			summary[2].Mean, summary[1].Mean)
+	}
+
+	var buf bytes.Buffer
+	if err := res.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := "scenario Interval Service replication seed object statistic value"
+	if got := records[0]; len(got) != 8 || got[1] != "Interval" || got[2] != "Service" {
+		t.Error("Header, expected", header, "got", got)
+	}
+	if len(records) != len(res.Rows)+1 {
+		t.Error("Records, expected", len(res.Rows)+1, "got", len(records))
+	}
+
+	// Replications are repeated exactly
+	if again := e.Run(); !reflect.DeepEqual(again.Rows, res.Rows) {
+		t.Error("Rows of repeated experiment, expected the same rows")
+	}
+}
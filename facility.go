@@ -25,8 +25,10 @@ type Facility struct {
 	HoldedTransactID int
 	// For backuping Facility/Bifacility name if we includes Facility in Bifacility
 	bakupFacilityName string
-	// For counting the advance of transact
-	sum_advance float64
+	// Busy state of facility, 1 - busy, 0 - empty
+	busy *TimeWeighted
+	// Quantiles of holding times
+	holding *QuantileSketch
 	// For counting the transacts that go through Bifacility
 	cnt_transact float64
 }
@@ -40,20 +42,21 @@ func NewFacility(name string, interval, modificator int) *Facility {
 	obj.Interval = interval
 	obj.Modificator = modificator
 	obj.HoldedTransactID = -1
+	obj.busy = NewTimeWeighted(0, 0)
+	obj.holding = NewQuantileSketch(DefaultQuantileAccuracy, DefaultQuantileBuckets)
 	return obj
 }
 
 func (obj *Facility) GenerateAdvance() int {
 	advance := obj.Interval
 	if obj.Modificator > 0 {
-		advance += GetRandom(-obj.Modificator, obj.Modificator)
+		advance += obj.GetRandom(-obj.Modificator, obj.Modificator)
 	}
 	return advance
 }
 
 func (obj *Facility) HandleTransact(transact ITransaction) {
 	transact.DecTiсks()
-	transact.PrintInfo()
 	if transact.IsTheEnd() {
 		if obj.bakupFacilityName != "" {
 			transact.SetParameters([]Parameter{{Name: "Facility",
@@ -61,10 +64,13 @@ func (obj *Facility) HandleTransact(transact ITransaction) {
 		} else {
 			transact.SetParameters([]Parameter{{Name: "Facility", Value: nil}})
 		}
+		holding := transact.GetTimeInHolder()
 		for _, v := range obj.GetDst() {
-			if v.AppendTransact(transact) {
+			if SendTransact(obj, v, transact) {
+				obj.holding.Add(float64(holding))
 				obj.tb.Remove(transact)
 				obj.HoldedTransactID = -1
+				obj.busy.Update(obj.GetPipeline().GetModelTime(), 0)
 				return
 			}
 		}
@@ -78,7 +84,7 @@ func (obj *Facility) HandleTransacts(wg *sync.WaitGroup) {
 	}
 	go func() {
 		defer wg.Done()
-		transacts := obj.tb.GetItems()
+		transacts := getStepItems(obj.tb)
 		for _, tr := range transacts {
 			obj.HandleTransact(tr.transact)
 		}
@@ -90,11 +96,8 @@ func (obj *Facility) AppendTransact(transact ITransaction) bool {
 		// Facility is busy
 		return false
 	}
-	obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Facility")
 	transact.SetHolderName(obj.name)
-	advance := obj.GenerateAdvance()
-	obj.sum_advance += float64(advance)
-	transact.SetTiсks(advance)
+	transact.SetTiсks(obj.GenerateAdvance())
 	if transact.GetParameterByName("Facility") != nil {
 		obj.bakupFacilityName = transact.GetParameterByName("Facility").(string)
 	}
@@ -102,14 +105,26 @@ func (obj *Facility) AppendTransact(transact ITransaction) bool {
 	obj.HoldedTransactID = transact.GetId()
 	obj.tb.Push(transact)
 	obj.cnt_transact++
+	obj.busy.Update(obj.GetPipeline().GetModelTime(), 1)
 	return true
 }
 
+// Get time-weighted statistics of busy state of facility, mean is utilization
+func (obj *Facility) GetBusyStat() *TimeWeighted {
+	return obj.busy
+}
+
+// Get quantiles of holding times of facility
+func (obj *Facility) GetHoldingStat() *QuantileSketch {
+	return obj.holding
+}
+
 func (obj *Facility) PrintReport() {
 	obj.BaseObj.PrintReport()
-	avr := obj.sum_advance / obj.cnt_transact
+	modelTime := obj.GetPipeline().GetModelTime()
+	avr := obj.busy.GetArea(modelTime) / obj.cnt_transact
 	fmt.Printf("Average advance %.2f \tAverage utilization %.2f%%\tNumber entries %.2f \t", avr,
-		100*avr*obj.cnt_transact/float64(obj.GetPipeline().GetSimTime()), obj.cnt_transact)
+		100*obj.busy.GetMean(modelTime), obj.cnt_transact)
 	if obj.HoldedTransactID > 0 {
 		fmt.Print("Transact ", obj.HoldedTransactID, " in facility")
 		part, _, parent_id := obj.tb.GetItem(obj.HoldedTransactID).transact.GetParts()
@@ -119,7 +134,9 @@ func (obj *Facility) PrintReport() {
 	} else {
 		fmt.Print("Facility is empty")
 	}
-	fmt.Printf("\n\n")
+	fmt.Println()
+	obj.holding.PrintReport("Holding time")
+	fmt.Println()
 }
 
 func (obj *Facility) IsEmpty() bool {
@@ -129,3 +146,34 @@ func (obj *Facility) IsEmpty() bool {
 	}
 	return true
 }
+
+type facilityState struct {
+	HoldedTransactID  int
+	BakupFacilityName string
+	Busy              timeWeightedState
+	Holding           quantileSketchState
+	CntTransact       float64
+}
+
+func (obj *Facility) SaveState() ([]byte, error) {
+	return encodeState(facilityState{
+		HoldedTransactID:  obj.HoldedTransactID,
+		BakupFacilityName: obj.bakupFacilityName,
+		Busy:              obj.busy.getState(),
+		Holding:           obj.holding.getState(),
+		CntTransact:       obj.cnt_transact,
+	})
+}
+
+func (obj *Facility) LoadState(data []byte) error {
+	state := facilityState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	obj.HoldedTransactID = state.HoldedTransactID
+	obj.bakupFacilityName = state.BakupFacilityName
+	obj.busy.setState(state.Busy)
+	obj.holding.setState(state.Holding)
+	obj.cnt_transact = state.CntTransact
+	return nil
+}
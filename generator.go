@@ -25,7 +25,7 @@ type Generator struct {
 	Start       int            // Start delay time
 	Count       int            // Creation limit. Max count of transactions.
 	id          int            // ID of new transaction
-	nextborn    int            // The time when will create new transaction
+	nextborn    int            // The time when will create new transaction, -1 if not calculated yet
 	HandleBorn  HandleBornFunc // Function for generate born time of transaction
 }
 
@@ -34,7 +34,7 @@ func GenerateBorn(obj *Generator) int {
 	var born int
 	born += obj.Interval
 	if obj.Modificator > 0 {
-		born += GetRandom(-obj.Modificator, obj.Modificator)
+		born += obj.GetRandom(-obj.Modificator, obj.Modificator)
 	}
 	if obj.GetPipeline() != nil {
 		born += obj.GetPipeline().GetModelTime()
@@ -60,25 +60,36 @@ func NewGenerator(name string, interval, modificator, start, count int, hndl Han
 	} else {
 		obj.HandleBorn = GenerateBorn
 	}
-	obj.nextborn = obj.HandleBorn(obj)
+	// The first born time is calculated at start of simulation, when random
+	// stream of pipeline is available
+	obj.nextborn = -1
 	return obj
 }
 
 // Generates transaction and it send into the simulation
 func (obj *Generator) GenerateTransact() {
 	var isTransactSended bool
-	obj.GetLogger().GetTrace().Println("Generate transact ", obj.id)
 	t := NewTransaction(obj.GetPipeline().GetIDNewTransaction(), obj.GetPipeline())
 	t.SetHolderName(obj.name)
+	for _, o := range getObservers(obj.GetPipeline()) {
+		o.OnTransactionCreated(obj, t)
+	}
+	// Generated transact is in generator until it is sent
+	addCounters(obj, 1, 1)
 	for _, v := range obj.GetDst() {
-		isTransactSended = isTransactSended || v.AppendTransact(t)
+		isTransactSended = isTransactSended || SendTransact(obj, v, t)
 	}
 	if isTransactSended {
 		obj.id++
+	} else {
+		addCounters(obj, -1, -1)
 	}
 }
 
 func (obj *Generator) HandleTransacts(wg *sync.WaitGroup) {
+	if obj.nextborn < 0 {
+		obj.nextborn = obj.HandleBorn(obj)
+	}
 	if (obj.Count != 0 && obj.id > obj.Count) ||
 		(obj.nextborn != obj.GetPipeline().GetModelTime()) {
 		wg.Done()
@@ -98,7 +109,6 @@ func (obj *Generator) HandleTransacts(wg *sync.WaitGroup) {
 		for {
 			obj.GenerateTransact()
 			if obj.id > obj.Count {
-				obj.GetLogger().GetTrace().Println("Stop generate")
 				return
 			}
 		}
@@ -110,3 +120,21 @@ func (obj *Generator) PrintReport() {
 	fmt.Println("Generated", obj.id-1)
 	fmt.Println()
 }
+
+type generatorState struct {
+	ID       int
+	Nextborn int
+}
+
+func (obj *Generator) SaveState() ([]byte, error) {
+	return encodeState(generatorState{ID: obj.id, Nextborn: obj.nextborn})
+}
+
+func (obj *Generator) LoadState(data []byte) error {
+	state := generatorState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	obj.id, obj.nextborn = state.ID, state.Nextborn
+	return nil
+}
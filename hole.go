@@ -12,9 +12,11 @@ import (
 // Hole in which fall in transactions
 type Hole struct {
 	BaseObj
-	sum_life     float64 // For count average transact life
-	sum_advance  float64 // For count average advance
-	cnt_transact float64 // How much killed
+	sum_life     float64         // For count average transact life
+	sum_advance  float64         // For count average advance
+	cnt_transact float64         // How much killed
+	life         *QuantileSketch // Quantiles of transact life
+	lifeSeries   *Observations   // Lifes of killed transacts in order of killing, nil if disabled
 }
 
 // Creates new Hole
@@ -22,45 +24,101 @@ type Hole struct {
 func NewHole(name string) *Hole {
 	obj := &Hole{}
 	obj.BaseObj.Init(name)
+	obj.life = NewQuantileSketch(DefaultQuantileAccuracy, DefaultQuantileBuckets)
 	return obj
 }
 
 func (obj *Hole) HandleTransact(transact ITransaction) {
 	if !transact.IsKilled() {
 		transact.Kill()
-		transact.PrintInfo()
+		addCounters(obj, 0, -1)
+		for _, o := range getObservers(obj.GetPipeline()) {
+			o.OnKilled(obj, transact)
+		}
 		obj.sum_life += float64(transact.GetLife())
+		obj.life.Add(float64(transact.GetLife()))
+		if obj.lifeSeries != nil {
+			obj.lifeSeries.Add(float64(transact.GetLife()))
+		}
 		obj.sum_advance += float64(transact.GetAdvanceTime())
 		obj.cnt_transact++
 	}
 }
 
+// Transacts are killed on entering, so there is nothing to handle
 func (obj *Hole) HandleTransacts(wg *sync.WaitGroup) {
-	if obj.tb.GetLen() == 0 {
-		wg.Done()
-		return
-	}
-	go func() {
-		defer wg.Done()
-		transacts := obj.tb.GetItems()
-		defer obj.tb.UnlockTable()
-		for _, tr := range transacts {
-			obj.HandleTransact(tr.transact)
-		}
-	}()
+	wg.Done()
 }
 
+// Killed transacts are kept in table only for export of journal, so memory
+// doesn't grow with count of transacts
 func (obj *Hole) AppendTransact(transact ITransaction) bool {
-	obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Hole")
 	transact.SetHolderName(obj.name)
-	obj.tb.Push(transact)
+	if isJournalEnabled(obj.GetPipeline()) {
+		obj.tb.Push(transact)
+	}
+	obj.HandleTransact(transact)
 	return true
 }
 
+// Get quantiles of life of killed transacts
+func (obj *Hole) GetLifeStat() *QuantileSketch {
+	return obj.life
+}
+
+// Enable recording of lifes of killed transacts in order of killing, for
+// output analysis. Series keeps all lifes, so it must be enabled only when it
+// is needed. It must be called before start of simulation.
+func (obj *Hole) EnableSeries() {
+	if obj.lifeSeries == nil {
+		obj.lifeSeries = NewObservations()
+	}
+}
+
+// Get lifes of killed transacts in order of killing, nil if recording is not
+// enabled
+func (obj *Hole) GetLifeSeries() *Observations {
+	return obj.lifeSeries
+}
+
 func (obj *Hole) PrintReport() {
 	obj.BaseObj.PrintReport()
 	fmt.Println("Killed", obj.cnt_transact)
 	fmt.Printf("Average advance %.2f\n", obj.sum_advance/obj.cnt_transact)
 	fmt.Printf("Average life %.2f\n", obj.sum_life/obj.cnt_transact)
+	obj.life.PrintReport("Life")
+	if obj.lifeSeries != nil {
+		obj.lifeSeries.PrintReport("Life")
+	}
 	fmt.Println()
 }
+
+type holeState struct {
+	SumLife     float64
+	SumAdvance  float64
+	CntTransact float64
+	Life        quantileSketchState
+	LifeSeries  []float64
+}
+
+func (obj *Hole) SaveState() ([]byte, error) {
+	state := holeState{SumLife: obj.sum_life, SumAdvance: obj.sum_advance,
+		CntTransact: obj.cnt_transact, Life: obj.life.getState()}
+	if obj.lifeSeries != nil {
+		state.LifeSeries = obj.lifeSeries.GetValues()
+	}
+	return encodeState(state)
+}
+
+func (obj *Hole) LoadState(data []byte) error {
+	state := holeState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	obj.sum_life, obj.sum_advance, obj.cnt_transact = state.SumLife, state.SumAdvance, state.CntTransact
+	obj.life.setState(state.Life)
+	if obj.lifeSeries != nil {
+		obj.lifeSeries.setValues(state.LifeSeries)
+	}
+	return nil
+}
@@ -31,3 +31,24 @@ func TestHole_HandleTransact(t *testing.T) {
 		t.Error("Transact sum_life, expected", advance, "got", hole.sum_advance)
 	}
 }
+
+func TestHole_AppendTransact(t *testing.T) {
+	for _, journal := range []bool{false, true} {
+		pipe := NewPipeline("pipe", false)
+		hole := NewHole("hole")
+		pipe.Append(hole)
+		pipe.EnableJournal(journal)
+		transact := NewTransaction(1, pipe)
+		hole.AppendTransact(transact)
+		if !transact.IsKilled() {
+			t.Error("Transact killing, expected", true, "got", transact.IsKilled())
+		}
+		expected := 0
+		if journal {
+			expected = 1
+		}
+		if hole.tb.GetLen() != expected {
+			t.Error("Kept killed transacts with journal", journal, "expected", expected, "got", hole.tb.GetLen())
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"os"
+	"time"
+)
+
+// Options of HTML report
+type HTMLReportOptions struct {
+	Title        string   // Title of report, name of pipeline by default
+	Replications int      // Count of replications of run, 0 - not shown
+	Sampler      *Sampler // Sampler for charts of series, nil - no charts
+}
+
+type htmlChart struct {
+	Title string
+	SVG   template.HTML
+}
+
+type htmlReport struct {
+	Title        string
+	Version      string
+	Generated    string
+	Seed         int64
+	SimTime      int
+	ModelTime    int
+	Replications int
+	Warnings     []string
+	Diagram      template.HTML
+	Report       *StandardReport
+	Charts       []htmlChart
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th { background: #eef3f8; }
+td:first-child, th:first-child { text-align: left; }
+.warning { background: #fff3cd; border: 1px solid #e0c060; padding: 8px; margin-bottom: 8px; white-space: pre-wrap; }
+.chart { display: inline-block; margin: 0 1em 1em 0; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<tr><td>Generated</td><td>{{.Generated}}</td></tr>
+<tr><td>Library version</td><td>{{.Version}}</td></tr>
+<tr><td>Seed</td><td>{{.Seed}}</td></tr>
+<tr><td>Simulation time</td><td>{{.SimTime}}</td></tr>
+<tr><td>Model time</td><td>{{.ModelTime}}</td></tr>
+{{if .Replications}}<tr><td>Replications</td><td>{{.Replications}}</td></tr>{{end}}
+</table>
+{{if .Warnings}}<h2>Warnings</h2>
+{{range .Warnings}}<div class="warning">{{.}}</div>
+{{end}}{{end}}
+<h2>Model</h2>
+{{.Diagram}}
+<h2>Blocks</h2>
+<table>
+<tr><th>Label</th><th>Loc</th><th>Block type</th><th>Entry count</th><th>Current count</th><th>Refusals</th></tr>
+{{range .Report.Blocks}}<tr><td>{{.Name}}</td><td>{{.Loc}}</td><td>{{.Type}}</td><td>{{.EntryCount}}</td><td>{{.CurrentCount}}</td><td>{{.Refusals}}</td></tr>
+{{end}}</table>
+{{if .Report.Facilities}}<h2>Facilities</h2>
+<table>
+<tr><th>Facility</th><th>Entries</th><th>Util.</th><th>Ave. time</th><th>Avail.</th><th>Owner</th><th>Delay</th></tr>
+{{range .Report.Facilities}}<tr><td>{{.Name}}</td><td>{{.Entries}}</td><td>{{printf "%.3f" .Util}}</td><td>{{printf "%.3f" .AveTime}}</td><td>{{.Avail}}</td><td>{{.Owner}}</td><td>{{.Delay}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Report.Queues}}<h2>Queues</h2>
+<table>
+<tr><th>Queue</th><th>Max</th><th>Cont.</th><th>Entry</th><th>Entry(0)</th><th>Ave. cont.</th><th>Ave. time</th><th>Ave.(-0)</th></tr>
+{{range .Report.Queues}}<tr><td>{{.Name}}</td><td>{{.Max}}</td><td>{{.Cont}}</td><td>{{.Entry}}</td><td>{{.Entry0}}</td><td>{{printf "%.3f" .AveCont}}</td><td>{{printf "%.3f" .AveTime}}</td><td>{{printf "%.3f" .AveTimeNonZero}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Report.Savevalues}}<h2>Savevalues</h2>
+<table>
+<tr><th>Savevalue</th><th>Value</th></tr>
+{{range .Report.Savevalues}}<tr><td>{{.Name}}</td><td>{{printf "%.3f" .Value}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Report.Quantiles}}<h2>Quantiles</h2>
+<table>
+<tr><th>Object</th><th>Kind</th><th>Count</th><th>p50</th><th>p90</th><th>p95</th><th>p99</th><th>Max</th></tr>
+{{range .Report.Quantiles}}<tr><td>{{.Name}}</td><td>{{.Kind}}</td><td>{{.Count}}</td><td>{{printf "%.3f" .P50}}</td><td>{{printf "%.3f" .P90}}</td><td>{{printf "%.3f" .P95}}</td><td>{{printf "%.3f" .P99}}</td><td>{{printf "%.3f" .Max}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Report.Tables}}<h2>Tables</h2>
+<table>
+<tr><th>Table</th><th>Entries</th><th>Mean</th><th>Std. dev.</th></tr>
+{{range .Report.Tables}}<tr><td>{{.Name}}</td><td>{{.Entries}}</td><td>{{printf "%.3f" .Mean}}</td><td>{{printf "%.3f" .StdDev}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Charts}}<h2>Charts</h2>
+{{range .Charts}}<div class="chart" title="{{.Title}}">{{.SVG}}</div>
+{{end}}{{end}}
+</body>
+</html>
+`))
+
+// Write self-contained HTML report about run: metadata, warnings, diagram of
+// model, statistics and charts
+func (p *Pipeline) WriteHTMLReport(w io.Writer, opts HTMLReportOptions) error {
+	data := htmlReport{
+		Title:        opts.Title,
+		Version:      Version,
+		Generated:    time.Now().Format(time.RFC3339),
+		Seed:         p.seed,
+		SimTime:      p.simTime,
+		ModelTime:    p.modelTime,
+		Replications: opts.Replications,
+		Report:       p.GetStandardReport(),
+	}
+	if data.Title == "" {
+		data.Title = p.name
+	}
+	if p.stall.report != nil {
+		data.Warnings = append(data.Warnings, p.stall.report.String())
+	}
+	for _, v := range p.Validate() {
+		data.Warnings = append(data.Warnings, v.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteDiagramSVG(&buf); err != nil {
+		return err
+	}
+	data.Diagram = template.HTML(buf.String())
+	chart := func(title string, write func(w io.Writer) error) error {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			return err
+		}
+		data.Charts = append(data.Charts, htmlChart{Title: title, SVG: template.HTML(buf.String())})
+		return nil
+	}
+	if len(data.Report.Facilities) > 0 {
+		if err := chart("Utilization", p.WriteUtilizationSVG); err != nil {
+			return err
+		}
+	}
+	for _, t := range p.GetTables() {
+		t := t
+		err := chart(t.GetName(), func(w io.Writer) error {
+			return WriteHistogramSVG(w, t.GetName(), t)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	// Histograms of queues without tables and of life in holes
+	for _, o := range p.GetSortedObjects() {
+		switch obj := o.(type) {
+		case *Queue:
+			if obj.qtable == nil && obj.times.GetCount() > 0 {
+				if err := chart(obj.name+" time in queue", obj.WriteTimeHistogramSVG); err != nil {
+					return err
+				}
+			}
+		case *Hole:
+			if obj.life.GetCount() > 0 {
+				if err := chart(obj.name+" life", obj.WriteLifeHistogramSVG); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if opts.Sampler != nil {
+		err := chart("Series", func(w io.Writer) error {
+			return opts.Sampler.WriteSVG(w, "Series")
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return htmlReportTemplate.Execute(w, data)
+}
+
+// Write self-contained HTML report about run to file
+func (p *Pipeline) WriteHTMLReportFile(path string, opts HTMLReportOptions) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteHTMLReport(f, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPipeline_WriteHTMLReport(t *testing.T) {
+	pipe := NewPipeline("Barbershop <test>", false)
+	pipe.SetSeed(42)
+	g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+	q := NewQueue("Chairs")
+	waits, err := NewTable("Waits", 0, 2, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.SetQTable(waits)
+	f := NewFacility("Master", 16, 4)
+	h := NewHole("Out")
+	unused := NewHole("Unused")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	pipe.Append(unused)
+	pipe.AppendTable(waits)
+	sampler := pipe.EnableSampling(10, QueueLength("Chairs"))
+	pipe.SetSimTime(100)
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+
+	var buf bytes.Buffer
+	if err := pipe.WriteHTMLReport(&buf, HTMLReportOptions{Replications: 3, Sampler: sampler}); err != nil {
+		t.Fatal(err)
+	}
+	report := buf.String()
+	expected := []string{
+		"<title>Barbershop &lt;test&gt;</title>",
+		"<td>Seed</td><td>42</td>",
+		"<td>Replications</td><td>3</td>",
+		"<td>Library version</td><td>" + Version + "</td>",
+		"&#34;Unused&#34;: unreachable",
+		`title="Utilization"`,
+		`title="Waits"`,
+		`title="Out life"`,
+		`title="Series"`,
+		"<polyline",
+	}
+	for _, s := range expected {
+		if !strings.Contains(report, s) {
+			t.Error("Report, expected", s)
+		}
+	}
+	if strings.Contains(report, `title="Chairs time in queue"`) || strings.Contains(report, `title="Unused life"`) {
+		t.Error("Report, expected no histograms of queue with table and of empty hole")
+	}
+	// Diagram and charts
+	if n := strings.Count(report, "<svg"); n != 5 {
+		t.Error("SVG images, expected", 5, "got", n)
+	}
+}
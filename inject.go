@@ -0,0 +1,98 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"fmt"
+)
+
+// Inject new transact with parameters into object of running pipeline. It
+// waits for end of current step, so it must not be called from observers.
+// Returns error if object is not found, simulation is stopped or object
+// refused transact.
+func (p *Pipeline) Inject(name string, parameters ...Parameter) (ITransaction, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	obj, ok := p.objects[name]
+	if !ok {
+		return nil, fmt.Errorf("object %q not found", name)
+	}
+	if p.IsStopped() {
+		return nil, fmt.Errorf("simulation is stopped")
+	}
+	t := NewTransaction(p.GetIDNewTransaction(), p)
+	t.SetParameters(parameters)
+	for _, o := range p.observers {
+		o.OnTransactionCreated(obj, t)
+	}
+	if !p.sendTransact(nil, obj, t) {
+		return t, fmt.Errorf("object %q refused transact %d", name, t.GetId())
+	}
+	return t, nil
+}
+
+// Inject transacts into object for each set of parameters received from
+// channel, until channel is closed or simulation is stopped. Refused
+// transacts are printed to warning log.
+func (p *Pipeline) InjectFrom(name string, ch <-chan []Parameter) {
+	go func() {
+		for {
+			select {
+			case <-p.Done:
+				return
+			case parameters, ok := <-ch:
+				if !ok {
+					return
+				}
+				if _, err := p.Inject(name, parameters...); err != nil {
+					p.logger.Warning.Println("Inject:", err)
+				}
+			}
+		}
+	}()
+}
+
+// Observer which sends transacts leaving object to channel
+type outputObserver struct {
+	BaseObserver
+	name   string
+	ch     chan TransactInfo
+	logger ILogger
+}
+
+func (o *outputObserver) send(obj IBaseObj, transact ITransaction) {
+	if obj.GetName() != o.name {
+		return
+	}
+	select {
+	case o.ch <- newTransactInfo(transact):
+	default:
+		o.logger.GetWarning().Println("Output of", o.name, "is full, transact",
+			transact.GetId(), "is dropped")
+	}
+}
+
+func (o *outputObserver) OnLeave(obj IBaseObj, transact ITransaction) {
+	o.send(obj, transact)
+}
+
+func (o *outputObserver) OnKilled(obj IBaseObj, transact ITransaction) {
+	o.send(obj, transact)
+}
+
+func (o *outputObserver) OnStop(modelTime int) {
+	close(o.ch)
+}
+
+// Get channel of transacts leaving object or killed by it, channel has
+// buffer of size and is closed after last step of stopped simulation. Simulation is not
+// blocked by slow reader, if buffer is full, transact is dropped and warning
+// is printed to log. Transacts are sent as snapshots, because simulation
+// continues to change them. It must be called before start of simulation.
+func (p *Pipeline) Output(name string, size int) <-chan TransactInfo {
+	o := &outputObserver{name: name, ch: make(chan TransactInfo, size), logger: p.logger}
+	p.AddObserver(o)
+	return o.ch
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPipeline_Inject(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	q := NewQueue("Requests")
+	f := NewFacility("Service", 2, 0)
+	h := NewHole("Out")
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	served := pipe.Output("Service", 10)
+	killed := pipe.Output("Out", 10)
+
+	if _, err := pipe.Inject("Unknown"); err == nil {
+		t.Error("Inject into unknown object, expected error, got nil")
+	}
+	if err := pipe.Start(1000000); err != nil {
+		t.Fatal(err)
+	}
+	ch := make(chan []Parameter)
+	pipe.InjectFrom("Requests", ch)
+	if _, err := pipe.Inject("Requests", Parameter{Name: "Request", Value: 1}); err != nil {
+		t.Error("Inject, expected nil, got", err)
+	}
+	ch <- []Parameter{{Name: "Request", Value: 2}}
+
+	for i := 1; i <= 2; i++ {
+		select {
+		case info := <-killed:
+			if info.Parameters["Request"] != i || info.HolderName != "Out" {
+				t.Error("Killed transact, expected request", i, "in Out, got", info)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Killed transact, expected request", i, "got timeout")
+		}
+	}
+	if info := <-served; info.Parameters["Request"] != 1 {
+		t.Error("Served transact, expected request 1, got", info)
+	}
+	if c := pipe.GetBlockCounters("Requests"); c.Entries != 2 {
+		t.Error("Entries of Requests, expected 2, got", c.Entries)
+	}
+
+	pipe.Stop()
+	if _, err := pipe.Inject("Requests"); err == nil {
+		t.Error("Inject into stopped pipeline, expected error, got nil")
+	}
+	for range killed {
+	}
+}
+
+func TestPipeline_OutputStop(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 1, 0, 0, 0, nil)
+	h := NewHole("Out")
+	pipe.Append(g, h)
+	pipe.Append(h)
+	killed := pipe.Output("Out", 1)
+	if err := pipe.Start(1000000); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan int)
+	go func() {
+		n := 0
+		for range killed {
+			n++
+		}
+		done <- n
+	}()
+	time.Sleep(10 * time.Millisecond)
+	// Stop while simulation is sending transacts to output
+	pipe.Stop()
+	select {
+	case n := <-done:
+		if n == 0 {
+			t.Error("Killed transacts, expected not 0, got", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Output, expected closed channel, got timeout")
+	}
+}
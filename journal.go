@@ -0,0 +1,105 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// Record of journal of transact, entering and leaving of object
+type JournalRecord struct {
+	Object string `json:"object"` // Name of object
+	Enter  int    `json:"enter"`  // Model time of entering
+	Leave  int    `json:"leave"`  // Model time of leaving, -1 if transact is still in object
+}
+
+// Enable or disable journal of transacts. Journal records every entering and
+// leaving of objects by transacts with model time. Must be called before start
+// of simulation.
+func (p *Pipeline) EnableJournal(enable bool) {
+	p.journal = enable
+}
+
+// Is journal of transacts enabled?
+func (p *Pipeline) IsJournalEnabled() bool {
+	return p.journal
+}
+
+// Is journal of transacts enabled in pipeline? Other implementations of
+// IPipeline have no journal.
+func isJournalEnabled(pipe IPipeline) bool {
+	p, ok := pipe.(interface{ IsJournalEnabled() bool })
+	return ok && p.IsJournalEnabled()
+}
+
+// Forward transact from object which doesn't hold transacts (Check, Assign,
+// Count). Pass through object is recorded in journal only if forward is
+// successful, record is placed before records of next objects.
+func journalPass(name string, transact ITransaction, forward func() bool) bool {
+	t, ok := transact.(*Transaction)
+	if !ok || !isJournalEnabled(t.GetPipeline()) {
+		return forward()
+	}
+	mark := len(t.journal)
+	if !forward() {
+		return false
+	}
+	if mark > len(t.journal) {
+		mark = len(t.journal)
+	}
+	modelTime := t.GetPipeline().GetModelTime()
+	t.journal = append(t.journal[:mark], append([]JournalRecord{{Object: name,
+		Enter: modelTime, Leave: modelTime}}, t.journal[mark:]...)...)
+	return true
+}
+
+// Get all transacts for export of journal: live transacts and killed
+// transacts in Holes. Holes keep killed transacts only while journal is
+// enabled.
+func (p *Pipeline) GetJournalTransacts() []ITransaction {
+	return p.collectTransacts(true)
+}
+
+// Write journals of transacts in CSV format, one record per line
+func WriteJournalCSV(w io.Writer, transacts []ITransaction) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"transact", "part", "parent", "object", "enter", "leave"}); err != nil {
+		return err
+	}
+	for _, t := range transacts {
+		part, _, parent_id := t.GetParts()
+		for _, r := range t.GetJournal() {
+			err := cw.Write([]string{strconv.Itoa(t.GetId()), strconv.Itoa(part),
+				strconv.Itoa(parent_id), r.Object, strconv.Itoa(r.Enter), strconv.Itoa(r.Leave)})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Write journals of transacts in JSON lines format, one transact per line
+func WriteJournalJSON(w io.Writer, transacts []ITransaction) error {
+	enc := json.NewEncoder(w)
+	for _, t := range transacts {
+		part, parts, parent_id := t.GetParts()
+		err := enc.Encode(struct {
+			Transact int             `json:"transact"`
+			Part     int             `json:"part"`
+			Parts    int             `json:"parts"`
+			Parent   int             `json:"parent"`
+			Journal  []JournalRecord `json:"journal"`
+		}{t.GetId(), part, parts, parent_id, t.GetJournal()})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
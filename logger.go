@@ -7,6 +7,7 @@ package gpss
 import (
 	"io"
 	"log"
+	"log/slog"
 )
 
 type ILogger interface {
@@ -14,6 +15,7 @@ type ILogger interface {
 	GetInfo() *log.Logger
 	GetWarning() *log.Logger
 	GetError() *log.Logger
+	GetStructured() *slog.Logger
 }
 
 type Logger struct {
@@ -21,6 +23,13 @@ type Logger struct {
 	Info    *log.Logger
 	Warning *log.Logger
 	Error   *log.Logger
+	// Structured logger for tracing, writes to trace handle with debug level
+	// while trace is enabled
+	Structured *slog.Logger
+	// Handle of trace log, it is replaced by io.Discard while trace is disabled
+	traceHandle io.Writer
+	// Level of structured log
+	level slog.LevelVar
 }
 
 func NewLogger(
@@ -46,9 +55,31 @@ func NewLogger(
 		"ERROR: ",
 		log.Ldate|log.Ltime|log.Lshortfile)
 
+	logger.traceHandle = traceHandle
+	logger.level.Set(slog.LevelDebug)
+	logger.Structured = slog.New(slog.NewTextHandler(traceHandle,
+		&slog.HandlerOptions{Level: &logger.level}))
+
 	return logger
 }
 
+// Enable or disable trace log. While trace is disabled, events of structured
+// log are filtered before formatting.
+func (logger *Logger) SetTraceEnabled(enabled bool) {
+	if enabled {
+		logger.Trace.SetOutput(logger.traceHandle)
+		logger.level.Set(slog.LevelDebug)
+	} else {
+		logger.Trace.SetOutput(io.Discard)
+		logger.level.Set(slog.LevelInfo)
+	}
+}
+
+// Is trace log enabled?
+func (logger *Logger) IsTraceEnabled() bool {
+	return logger.level.Level() <= slog.LevelDebug
+}
+
 func (logger *Logger) GetTrace() *log.Logger {
 	return logger.Trace
 }
@@ -64,3 +95,7 @@ func (logger *Logger) GetWarning() *log.Logger {
 func (logger *Logger) GetError() *log.Logger {
 	return logger.Error
 }
+
+func (logger *Logger) GetStructured() *slog.Logger {
+	return logger.Structured
+}
@@ -0,0 +1,209 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Content type of OpenMetrics text format
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// MetricsHandler is HTTP handler which exposes statistics of pipelines in
+// OpenMetrics text format, metrics are labelled by names of pipeline and
+// object. Rates are calculated between scrapes.
+type MetricsHandler struct {
+	pipes []*Pipeline
+	mu    sync.Mutex
+	last  map[*Pipeline]metricsScrape // Previous scrapes for rates
+}
+
+// Values of previous scrape of pipeline
+type metricsScrape struct {
+	time      time.Time
+	modelTime int
+	events    int
+}
+
+// Statistics of object collected under lock of pipeline
+type blockMetrics struct {
+	BlockState
+	content     float64 // Time-average content of Queue
+	utilization float64 // Utilization of Facility and Bifacility
+	killed      int     // Killed transacts of Hole
+	isQueue     bool
+	isFacility  bool
+	isHole      bool
+}
+
+// Creates new MetricsHandler for pipelines
+func NewMetricsHandler(pipes ...*Pipeline) *MetricsHandler {
+	return &MetricsHandler{pipes: pipes, last: make(map[*Pipeline]metricsScrape)}
+}
+
+// Collect state and statistics of pipeline between steps
+func (p *Pipeline) collectMetrics() (*PipelineState, []blockMetrics) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state := p.getState()
+	blocks := make([]blockMetrics, 0, len(state.Blocks))
+	for _, b := range state.Blocks {
+		m := blockMetrics{BlockState: b}
+		switch obj := p.objects[b.Name].(type) {
+		case *Queue:
+			m.isQueue = true
+			m.content = obj.content.GetMean(p.modelTime)
+		case *Facility:
+			m.isFacility = true
+			m.utilization = obj.busy.GetMean(p.modelTime)
+		case *InFacility:
+			m.isFacility = true
+			m.utilization = obj.busy.GetMean(p.modelTime)
+		case *Hole:
+			m.isHole = true
+			m.killed = int(obj.cnt_transact)
+		}
+		blocks = append(blocks, m)
+	}
+	return state, blocks
+}
+
+// Escape value of label
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+type metricsWriter struct {
+	*bufio.Writer
+}
+
+func (w metricsWriter) family(name, typ, help string) {
+	fmt.Fprintf(w, "# TYPE %s %s\n# HELP %s %s\n", name, typ, name, help)
+}
+
+func (w metricsWriter) sample(name string, value float64, labels ...string) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, labels[i], escapeLabel(labels[i+1]))
+		}
+		w.WriteByte('}')
+	}
+	fmt.Fprintf(w, " %s\n", strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func (h *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	type collected struct {
+		pipe   *Pipeline
+		state  *PipelineState
+		blocks []blockMetrics
+		rate   float64 // Events per second
+		steps  float64 // Steps of model time per second
+	}
+	now := time.Now()
+	all := make([]collected, 0, len(h.pipes))
+	h.mu.Lock()
+	for _, p := range h.pipes {
+		c := collected{pipe: p}
+		c.state, c.blocks = p.collectMetrics()
+		events := 0
+		for _, b := range c.blocks {
+			events += b.Entries
+		}
+		if last, ok := h.last[p]; ok {
+			if dt := now.Sub(last.time).Seconds(); dt > 0 {
+				c.rate = float64(events-last.events) / dt
+				c.steps = float64(c.state.ModelTime-last.modelTime) / dt
+			}
+		}
+		h.last[p] = metricsScrape{time: now, modelTime: c.state.ModelTime, events: events}
+		all = append(all, c)
+	}
+	h.mu.Unlock()
+
+	w.Header().Set("Content-Type", OpenMetricsContentType)
+	mw := metricsWriter{bufio.NewWriter(w)}
+	// Run-level metrics
+	mw.family("gpss_model_time", "gauge", "Current model time.")
+	for _, c := range all {
+		mw.sample("gpss_model_time", float64(c.state.ModelTime), "pipeline", c.state.Name)
+	}
+	mw.family("gpss_sim_time", "gauge", "Simulation time.")
+	for _, c := range all {
+		mw.sample("gpss_sim_time", float64(c.state.SimTime), "pipeline", c.state.Name)
+	}
+	mw.family("gpss_running", "gauge", "1 if simulation is running, 0 if it is paused or stopped.")
+	for _, c := range all {
+		running := 0.0
+		if !c.state.Stopped && !c.state.Paused {
+			running = 1
+		}
+		mw.sample("gpss_running", running, "pipeline", c.state.Name)
+	}
+	mw.family("gpss_events_per_second", "gauge", "Entries of transacts to objects per second of wall time since previous scrape.")
+	for _, c := range all {
+		mw.sample("gpss_events_per_second", c.rate, "pipeline", c.state.Name)
+	}
+	mw.family("gpss_steps_per_second", "gauge", "Steps of model time per second of wall time since previous scrape.")
+	for _, c := range all {
+		mw.sample("gpss_steps_per_second", c.steps, "pipeline", c.state.Name)
+	}
+
+	// Block-level metrics
+	blockFamily := func(name, typ, help string, value func(b blockMetrics) (float64, bool)) {
+		mw.family(name, typ, help)
+		sampleName := name
+		if typ == "counter" {
+			sampleName += "_total"
+		}
+		for _, c := range all {
+			for _, b := range c.blocks {
+				if v, ok := value(b); ok {
+					mw.sample(sampleName, v, "pipeline", c.state.Name, "block", b.Name, "type", b.Type)
+				}
+			}
+		}
+	}
+	blockFamily("gpss_block_entries", "counter", "Transacts entered object, generated transacts for Generator.",
+		func(b blockMetrics) (float64, bool) { return float64(b.Entries), true })
+	blockFamily("gpss_block_refusals", "counter", "Refusals of object to accept transact.",
+		func(b blockMetrics) (float64, bool) { return float64(b.Refusals), true })
+	blockFamily("gpss_block_current", "gauge", "Transacts in object now.",
+		func(b blockMetrics) (float64, bool) { return float64(b.Current), true })
+	blockFamily("gpss_generated", "counter", "Transacts generated by Generator.",
+		func(b blockMetrics) (float64, bool) { return float64(b.Entries), b.Type == "GENERATE" })
+	blockFamily("gpss_killed", "counter", "Transacts killed by Hole.",
+		func(b blockMetrics) (float64, bool) { return float64(b.killed), b.isHole })
+	blockFamily("gpss_queue_length", "gauge", "Current length of queue.",
+		func(b blockMetrics) (float64, bool) {
+			if b.Length != nil {
+				return float64(*b.Length), true
+			}
+			return 0, false
+		})
+	blockFamily("gpss_queue_content_average", "gauge", "Time-average content of queue.",
+		func(b blockMetrics) (float64, bool) { return b.content, b.isQueue })
+	blockFamily("gpss_facility_busy", "gauge", "1 if facility is busy, 0 if it is empty.",
+		func(b blockMetrics) (float64, bool) {
+			if b.Busy != nil && *b.Busy {
+				return 1, b.isFacility
+			}
+			return 0, b.isFacility
+		})
+	blockFamily("gpss_facility_utilization", "gauge", "Time-average utilization of facility.",
+		func(b blockMetrics) (float64, bool) { return b.utilization, b.isFacility })
+	mw.WriteString("# EOF\n")
+	mw.Flush()
+}
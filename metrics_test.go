@@ -0,0 +1,58 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	pipe := NewPipeline(`Barber "shop"`, false)
+	g := NewGenerator("Clients", 5, 0, 0, 0, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 7, 0)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	pipe.SetSimTime(100)
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+
+	// Facility can be released at last step
+	busy := "0"
+	if !f.IsEmpty() {
+		busy = "1"
+	}
+	handler := NewMetricsHandler(pipe)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); ct != OpenMetricsContentType {
+		t.Error("Content type, expected", OpenMetricsContentType, "got", ct)
+	}
+	body := w.Body.String()
+	expected := []string{
+		`gpss_model_time{pipeline="Barber \"shop\""} 100`,
+		"# TYPE gpss_block_entries counter",
+		`gpss_generated_total{pipeline="Barber \"shop\"",block="Clients",type="GENERATE"} ` +
+			strconv.Itoa(pipe.GetBlockCounters("Clients").Entries),
+		`gpss_facility_busy{pipeline="Barber \"shop\"",block="Master",type="FACILITY"} ` + busy,
+		`gpss_queue_length{pipeline="Barber \"shop\"",block="Chairs",type="QUEUE"}`,
+		`gpss_killed_total{pipeline="Barber \"shop\"",block="Out",type="TERMINATE"}`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(body, s) {
+			t.Error("Metrics, expected", s, "got", body)
+		}
+	}
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Error("Metrics, expected # EOF at the end")
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+// IObserver receives events of pipeline and its objects. Transacts can be
+// injected and statistics can be read from other goroutines, so methods of
+// observer must be safe for concurrent use. Objects which forward transacts
+// immediately (Check, Assign, Queue) report success only after next object
+// accepted transact, so events of next objects can be received first.
+type IObserver interface {
+	// Transact is created by Generator
+	OnTransactionCreated(obj IBaseObj, transact ITransaction)
+	// Transact entered to object
+	OnEnter(obj IBaseObj, transact ITransaction)
+	// Transact left object
+	OnLeave(obj IBaseObj, transact ITransaction)
+	// Object refused transact, AppendTransact returned false
+	OnRefused(obj IBaseObj, transact ITransaction)
+	// Transact is splitted by Split to parts
+	OnSplit(obj IBaseObj, parent ITransaction, parts []ITransaction)
+	// Parts of transact are aggregated by Aggregate
+	OnAggregate(obj IBaseObj, transact ITransaction)
+	// Transact is killed by Hole
+	OnKilled(obj IBaseObj, transact ITransaction)
+	// All objects handled transacts at model time
+	OnTick(modelTime int)
+	// Simulation is stopped
+	OnStop(modelTime int)
+}
+
+// BaseObserver ignores all events, it can be embedded to observer which
+// handles only part of events
+type BaseObserver struct{}
+
+func (BaseObserver) OnTransactionCreated(obj IBaseObj, transact ITransaction)        {}
+func (BaseObserver) OnEnter(obj IBaseObj, transact ITransaction)                     {}
+func (BaseObserver) OnLeave(obj IBaseObj, transact ITransaction)                     {}
+func (BaseObserver) OnRefused(obj IBaseObj, transact ITransaction)                   {}
+func (BaseObserver) OnSplit(obj IBaseObj, parent ITransaction, parts []ITransaction) {}
+func (BaseObserver) OnAggregate(obj IBaseObj, transact ITransaction)                 {}
+func (BaseObserver) OnKilled(obj IBaseObj, transact ITransaction)                    {}
+func (BaseObserver) OnTick(modelTime int)                                            {}
+func (BaseObserver) OnStop(modelTime int)                                            {}
+
+// Add observer of events. Must be called before start of simulation.
+func (p *Pipeline) AddObserver(observer IObserver) {
+	p.observers = append(p.observers, observer)
+}
+
+// Get observers of events
+func (p *Pipeline) GetObservers() []IObserver {
+	return p.observers
+}
+
+// Get observers of pipeline, other implementations of IPipeline have no
+// observers
+func getObservers(pipe IPipeline) []IObserver {
+	if p, ok := pipe.(interface{ GetObservers() []IObserver }); ok {
+		return p.GetObservers()
+	}
+	return nil
+}
+
+// Sending and counting of transacts by pipeline, implemented by Pipeline
+type transactRouter interface {
+	sendTransact(src, dst IBaseObj, transact ITransaction) bool
+	addCounters(name string, entries, current int)
+}
+
+// Send transact from object src to object dst. Pipeline of src counts
+// entering, leaving and refusals of objects and notifies observers, so custom
+// objects must send transacts by SendTransact instead of AppendTransact of
+// destination.
+func SendTransact(src, dst IBaseObj, transact ITransaction) bool {
+	if r, ok := src.GetPipeline().(transactRouter); ok {
+		return r.sendTransact(src, dst, transact)
+	}
+	return dst.AppendTransact(transact)
+}
+
+// Send transact from object src to object dst, src is nil for transacts
+// injected from outside of model
+func (p *Pipeline) sendTransact(src, dst IBaseObj, transact ITransaction) bool {
+	counters := p.counters[dst.GetName()]
+	if !dst.AppendTransact(transact) {
+		if counters != nil {
+			counters.refusals.Add(1)
+		}
+		for _, o := range p.observers {
+			o.OnRefused(dst, transact)
+		}
+		return false
+	}
+	if counters != nil {
+		counters.entries.Add(1)
+		counters.current.Add(1)
+	}
+	if src != nil {
+		p.addCounters(src.GetName(), 0, -1)
+	}
+	for _, o := range p.observers {
+		if src != nil {
+			o.OnLeave(src, transact)
+		}
+		o.OnEnter(dst, transact)
+	}
+	return true
+}
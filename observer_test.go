@@ -0,0 +1,87 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"sync"
+	"testing"
+)
+
+type countingObserver struct {
+	BaseObserver
+	mu      sync.Mutex
+	created int
+	entered map[string]int
+	refused map[string]int
+	killed  int
+	ticks   int
+	stopped bool
+}
+
+func (o *countingObserver) OnTransactionCreated(obj IBaseObj, transact ITransaction) {
+	o.mu.Lock()
+	o.created++
+	o.mu.Unlock()
+}
+
+func (o *countingObserver) OnEnter(obj IBaseObj, transact ITransaction) {
+	o.mu.Lock()
+	o.entered[obj.GetName()]++
+	o.mu.Unlock()
+}
+
+func (o *countingObserver) OnRefused(obj IBaseObj, transact ITransaction) {
+	o.mu.Lock()
+	o.refused[obj.GetName()]++
+	o.mu.Unlock()
+}
+
+func (o *countingObserver) OnKilled(obj IBaseObj, transact ITransaction) {
+	o.mu.Lock()
+	o.killed++
+	o.mu.Unlock()
+}
+
+func (o *countingObserver) OnTick(modelTime int) {
+	o.ticks++
+}
+
+func (o *countingObserver) OnStop(modelTime int) {
+	o.stopped = true
+}
+
+func TestPipeline_AddObserver(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 0, 0, 0, 2, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 3, 0)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	observer := &countingObserver{entered: make(map[string]int), refused: make(map[string]int)}
+	pipe.AddObserver(observer)
+	pipe.SetSimTime(10)
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+
+	if observer.created != 2 {
+		t.Error("Created, expected", 2, "got", observer.created)
+	}
+	if observer.entered["Chairs"] != 2 || observer.entered["Master"] != 2 || observer.entered["Out"] != 2 {
+		t.Error("Entered, expected 2 for each object, got", observer.entered)
+	}
+	if observer.refused["Master"] == 0 {
+		t.Error("Refused by \"Master\", expected not zero")
+	}
+	if observer.killed != 2 {
+		t.Error("Killed, expected", 2, "got", observer.killed)
+	}
+	if observer.ticks != 10 || !observer.stopped {
+		t.Error("Ticks, expected", 10, "and stop, got", observer.ticks, observer.stopped)
+	}
+}
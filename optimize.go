@@ -0,0 +1,365 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Decision variable of optimization
+type Variable struct {
+	Name    string
+	Min     float64
+	Max     float64
+	Integer bool // Only integer values, for example number of facilities
+}
+
+// Function for computing value from results of replications of scenario
+type ObjectiveFunc func(res *ExperimentResults, scenario int) float64
+
+// Constraint Min <= Value <= Max
+type Constraint struct {
+	Name  string
+	Value ObjectiveFunc
+	Min   float64
+	Max   float64
+}
+
+// Candidate solution with results of its evaluation
+type Candidate struct {
+	Scenario  Scenario `json:"scenario"`
+	Objective float64  `json:"objective"`
+	Feasible  bool     `json:"feasible"`
+	Violation float64  `json:"violation"` // Sum of violations of constraints
+}
+
+// Result of optimization
+type OptimizationResult struct {
+	Method     string       `json:"method"`
+	Best       *Candidate   `json:"best"`
+	Candidates []*Candidate `json:"candidates"` // Evaluated candidates in order of evaluation
+}
+
+// Optimizer searches values of decision variables which minimize or maximize
+// objective subject to constraints. Each candidate is evaluated by
+// replications of model with common random numbers, evaluated candidates are
+// cached. Search with the same seed gives the same result.
+type Optimizer struct {
+	factory      ExperimentFactory
+	simTime      int
+	replications int
+	objective    ObjectiveFunc
+	minimize     bool
+	variables    []Variable
+	constraints  []Constraint
+	seed         int64
+	workers      int
+	rnd          *rand.Rand
+	cache        map[string]*Candidate
+}
+
+// Get mean of statistic of object over replications of scenario
+func MeanOf(object, statistic string) ObjectiveFunc {
+	return func(res *ExperimentResults, scenario int) float64 {
+		mean, _ := meanVariance(res.GetValues(scenario, object, statistic))
+		return mean
+	}
+}
+
+// Get value of decision variable of scenario
+func VariableValue(name string) ObjectiveFunc {
+	return func(res *ExperimentResults, scenario int) float64 {
+		return res.Scenarios[scenario][name]
+	}
+}
+
+// Constraint value <= max
+func AtMost(name string, value ObjectiveFunc, max float64) Constraint {
+	return Constraint{Name: name, Value: value, Min: math.Inf(-1), Max: max}
+}
+
+// Constraint value >= min
+func AtLeast(name string, value ObjectiveFunc, min float64) Constraint {
+	return Constraint{Name: name, Value: value, Min: min, Max: math.Inf(1)}
+}
+
+// Creates new Optimizer.
+// factory - function for building model; simTime - simulation time of each
+// run; replications - count of runs of each candidate; objective - function
+// for optimization; minimize - minimize or maximize objective
+func NewOptimizer(factory ExperimentFactory, simTime, replications int, objective ObjectiveFunc,
+	minimize bool) *Optimizer {
+	o := &Optimizer{
+		factory:      factory,
+		simTime:      simTime,
+		replications: replications,
+		objective:    objective,
+		minimize:     minimize,
+		workers:      runtime.GOMAXPROCS(0),
+		cache:        make(map[string]*Candidate),
+	}
+	o.SetSeed(1)
+	return o
+}
+
+// Append decision variable
+func (o *Optimizer) AddVariable(v Variable) {
+	o.variables = append(o.variables, v)
+}
+
+// Append constraint
+func (o *Optimizer) AddConstraint(c Constraint) {
+	o.constraints = append(o.constraints, c)
+}
+
+// Set seed of search and of replications, must be called before search
+func (o *Optimizer) SetSeed(seed int64) {
+	o.seed = seed
+	o.rnd = rand.New(rand.NewPCG(uint64(seed), 0))
+}
+
+// Set count of parallel runs, by default it is GOMAXPROCS
+func (o *Optimizer) SetWorkers(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	o.workers = workers
+}
+
+// Get key of scenario for cache
+func (o *Optimizer) getKey(s Scenario) string {
+	parts := make([]string, 0, len(o.variables))
+	for _, v := range o.variables {
+		parts = append(parts, strconv.FormatFloat(s[v.Name], 'g', -1, 64))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Evaluate scenarios, not cached scenarios are run in parallel
+func (o *Optimizer) evaluate(scenarios []Scenario) []*Candidate {
+	e := NewExperiment(o.factory, o.simTime, o.replications)
+	e.SetSeed(o.seed)
+	e.SetWorkers(o.workers)
+	added := make(map[string]bool)
+	for _, s := range scenarios {
+		key := o.getKey(s)
+		if _, ok := o.cache[key]; !ok && !added[key] {
+			added[key] = true
+			e.AddScenario(s)
+		}
+	}
+	if len(added) > 0 {
+		res := e.Run()
+		for i, s := range res.Scenarios {
+			c := &Candidate{Scenario: s, Feasible: true}
+			o.cache[o.getKey(s)] = c
+			if !o.hasRows(res, i) {
+				// Model isn't run, candidate is rejected
+				c.Feasible = false
+				c.Violation = math.Inf(1)
+				continue
+			}
+			c.Objective = o.objective(res, i)
+			for _, con := range o.constraints {
+				v := con.Value(res, i)
+				switch {
+				case v < con.Min:
+					c.Violation += con.Min - v
+				case v > con.Max:
+					c.Violation += v - con.Max
+				}
+			}
+			if c.Violation > 0 {
+				c.Feasible = false
+			}
+		}
+	}
+	candidates := make([]*Candidate, len(scenarios))
+	for i, s := range scenarios {
+		candidates[i] = o.cache[o.getKey(s)]
+	}
+	return candidates
+}
+
+func (o *Optimizer) hasRows(res *ExperimentResults, scenario int) bool {
+	for _, r := range res.Rows {
+		if r.Scenario == scenario {
+			return true
+		}
+	}
+	return false
+}
+
+// Is candidate a better than b? Feasible candidate is better than infeasible,
+// infeasible candidates are compared by violation of constraints.
+func (o *Optimizer) isBetter(a, b *Candidate) bool {
+	if b == nil {
+		return true
+	}
+	if a.Feasible != b.Feasible {
+		return a.Feasible
+	}
+	if !a.Feasible {
+		return a.Violation < b.Violation
+	}
+	if o.minimize {
+		return a.Objective < b.Objective
+	}
+	return a.Objective > b.Objective
+}
+
+// Get value of variable in its bounds
+func (o *Optimizer) clamp(v Variable, x float64) float64 {
+	if v.Integer {
+		x = math.Round(x)
+	}
+	return math.Max(v.Min, math.Min(v.Max, x))
+}
+
+func (o *Optimizer) randomScenario() Scenario {
+	s := Scenario{}
+	for _, v := range o.variables {
+		if v.Integer {
+			s[v.Name] = v.Min + float64(o.rnd.IntN(int(v.Max-v.Min)+1))
+		} else {
+			s[v.Name] = v.Min + o.rnd.Float64()*(v.Max-v.Min)
+		}
+	}
+	return s
+}
+
+// Get neighbour of scenario, one random variable is mutated
+func (o *Optimizer) neighbour(s Scenario) Scenario {
+	n := Scenario{}
+	for k, v := range s {
+		n[k] = v
+	}
+	v := o.variables[o.rnd.IntN(len(o.variables))]
+	n[v.Name] = o.mutate(v, s[v.Name])
+	return n
+}
+
+// Change value of variable by normal step with deviation of tenth of its
+// range, integer variable is changed at least by 1
+func (o *Optimizer) mutate(v Variable, x float64) float64 {
+	step := o.rnd.NormFloat64() * (v.Max - v.Min) / 10
+	if v.Integer && math.Abs(step) < 1 {
+		step = math.Copysign(1, step)
+	}
+	return o.clamp(v, x+step)
+}
+
+func (o *Optimizer) newResult(method string, candidates []*Candidate) *OptimizationResult {
+	r := &OptimizationResult{Method: method, Candidates: candidates}
+	for _, c := range candidates {
+		if o.isBetter(c, r.Best) {
+			r.Best = c
+		}
+	}
+	return r
+}
+
+// Evaluate random candidates, all of them are run in parallel
+func (o *Optimizer) RandomSearch(evaluations int) *OptimizationResult {
+	scenarios := make([]Scenario, evaluations)
+	for i := range scenarios {
+		scenarios[i] = o.randomScenario()
+	}
+	return o.newResult("random search", o.evaluate(scenarios))
+}
+
+// Simulated annealing from random candidate. Worse neighbour is accepted with
+// probability exp(-delta/temperature), temperature is multiplied by cooling
+// after each evaluation.
+func (o *Optimizer) SimulatedAnnealing(evaluations int, temperature, cooling float64) *OptimizationResult {
+	current := o.evaluate([]Scenario{o.randomScenario()})[0]
+	candidates := []*Candidate{current}
+	for i := 1; i < evaluations; i++ {
+		next := o.evaluate([]Scenario{o.neighbour(current.Scenario)})[0]
+		candidates = append(candidates, next)
+		if o.isBetter(next, current) {
+			current = next
+		} else if next.Feasible == current.Feasible && temperature > 0 {
+			delta := next.Violation - current.Violation
+			if next.Feasible {
+				delta = math.Abs(next.Objective - current.Objective)
+			}
+			if o.rnd.Float64() < math.Exp(-delta/temperature) {
+				current = next
+			}
+		}
+		temperature *= cooling
+	}
+	return o.newResult("simulated annealing", candidates)
+}
+
+// Genetic algorithm: candidates of each generation are run in parallel,
+// parents are selected by tournament, children are made by uniform crossover
+// and mutation of each variable with probability mutation. Best candidate is
+// kept in next generation.
+func (o *Optimizer) Genetic(population, generations int, mutation float64) *OptimizationResult {
+	if population < 2 {
+		population = 2
+	}
+	scenarios := make([]Scenario, population)
+	for i := range scenarios {
+		scenarios[i] = o.randomScenario()
+	}
+	var candidates []*Candidate
+	current := o.evaluate(scenarios)
+	candidates = append(candidates, current...)
+	tournament := func() *Candidate {
+		a, b := current[o.rnd.IntN(len(current))], current[o.rnd.IntN(len(current))]
+		if o.isBetter(b, a) {
+			return b
+		}
+		return a
+	}
+	for g := 1; g < generations; g++ {
+		sort.SliceStable(current, func(i, j int) bool { return o.isBetter(current[i], current[j]) })
+		scenarios = []Scenario{current[0].Scenario}
+		for len(scenarios) < population {
+			p1, p2 := tournament(), tournament()
+			child := Scenario{}
+			for _, v := range o.variables {
+				child[v.Name] = p1.Scenario[v.Name]
+				if o.rnd.IntN(2) == 1 {
+					child[v.Name] = p2.Scenario[v.Name]
+				}
+				if o.rnd.Float64() < mutation {
+					child[v.Name] = o.mutate(v, child[v.Name])
+				}
+			}
+			scenarios = append(scenarios, child)
+		}
+		current = o.evaluate(scenarios)
+		candidates = append(candidates, current[1:]...)
+	}
+	return o.newResult("genetic algorithm", candidates)
+}
+
+// Print result of optimization
+func (r *OptimizationResult) PrintReport() {
+	fmt.Println("Optimization by", r.Method, "evaluated", len(r.Candidates), "candidates")
+	if r.Best == nil {
+		return
+	}
+	names := make([]string, 0, len(r.Best.Scenario))
+	for k := range r.Best.Scenario {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	fmt.Println("Best candidate:")
+	for _, n := range names {
+		fmt.Printf("\t%s = %g\n", n, r.Best.Scenario[n])
+	}
+	fmt.Println("Objective", r.Best.Objective, "feasible", r.Best.Feasible, "violation", r.Best.Violation)
+}
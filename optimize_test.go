@@ -0,0 +1,64 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizer(t *testing.T) {
+	factory := func(s Scenario) *Pipeline {
+		pipe := NewPipeline("Barbershop", false)
+		g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+		q := NewQueue("Chairs")
+		f := NewFacility("Master", int(s["Service"]), 4)
+		h := NewHole("Out")
+		pipe.Append(g, q)
+		pipe.Append(q, f)
+		pipe.Append(f, h)
+		pipe.Append(h)
+		return pipe
+	}
+	// Slowest master is cheapest, but clients must wait less than 5 on average
+	newOptimizer := func() *Optimizer {
+		o := NewOptimizer(factory, 480, 2, VariableValue("Service"), false)
+		o.AddVariable(Variable{Name: "Service", Min: 5, Max: 30, Integer: true})
+		o.AddConstraint(AtMost("Queue time", MeanOf("Chairs", "ave_time"), 5))
+		return o
+	}
+	results := []*OptimizationResult{
+		newOptimizer().RandomSearch(10),
+		newOptimizer().SimulatedAnnealing(15, 2, 0.9),
+		newOptimizer().Genetic(6, 4, 0.3),
+	}
+	for _, r := range results {
+		best := r.Best
+		if best == nil || !best.Feasible {
+			t.Error(r.Method, "expected feasible best candidate, got", best)
+			continue
+		}
+		service := best.Scenario["Service"]
+		if service < 5 || service > 30 || service != math.Trunc(service) || best.Objective != service {
+			t.Error(r.Method, "expected integer service in [5, 30], got", best.Scenario)
+		}
+		for _, c := range r.Candidates {
+			if c.Feasible && c.Scenario["Service"] > service {
+				t.Error(r.Method, "expected best service", c.Scenario["Service"], "got", service)
+			}
+		}
+	}
+	// Search with the same seed is repeated exactly
+	if r := newOptimizer().SimulatedAnnealing(15, 2, 0.9); !reflect.DeepEqual(r, results[1]) {
+		t.Error("Repeated search, expected", results[1].Best, "got", r.Best)
+	}
+	// Overloaded master violates constraint
+	o := newOptimizer()
+	c := o.evaluate([]Scenario{{"Service": 30}, {"Service": 30}})
+	if c[0] != c[1] || c[0].Feasible || c[0].Violation <= 0 {
+		t.Error("Candidate with service 30, expected cached infeasible candidate, got", c[0])
+	}
+}
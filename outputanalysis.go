@@ -0,0 +1,186 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Default count of batches for batch means
+const DefaultBatches = 20
+
+// Minimal count of batches and minimal size of batch of batch means, interval
+// isn't computed for less observations
+const (
+	MinBatches   = 10
+	MinBatchSize = 10
+)
+
+// Size of batches of MSER-5
+const mserBatchSize = 5
+
+// Series of observations in order of recording, for example lifes of killed
+// transacts, for analysis of output of single long run
+type Observations struct {
+	mu     sync.Mutex
+	values []float64
+}
+
+// Result of analysis of observations
+type OutputAnalysis struct {
+	Count      int     `json:"count"`      // Count of observations
+	Truncation int     `json:"truncation"` // Count of observations deleted as warm-up
+	Mean       float64 `json:"mean"`       // Mean of observations in batches
+	Batches    int     `json:"batches"`
+	BatchSize  int     `json:"batch_size"`
+	Confidence float64 `json:"confidence"`
+	HalfWidth  float64 `json:"half_width"`
+	Lower      float64 `json:"lower"`
+	Upper      float64 `json:"upper"`
+	// Lag-1 autocorrelation of batch means, batches are too small if it is
+	// far from 0
+	Lag1 float64 `json:"lag1"`
+}
+
+// Creates new Observations.
+func NewObservations() *Observations {
+	return &Observations{}
+}
+
+// Add observation
+func (o *Observations) Add(value float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.values = append(o.values, value)
+}
+
+// Get count of observations
+func (o *Observations) GetCount() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.values)
+}
+
+// Get copy of observations
+func (o *Observations) GetValues() []float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]float64(nil), o.values...)
+}
+
+// Delete all observations
+func (o *Observations) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.values = nil
+}
+
+func (o *Observations) setValues(values []float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.values = values
+}
+
+// Delete warm-up by MSER-5 and get batch means confidence interval
+func (o *Observations) Analyze(confidence float64) (OutputAnalysis, error) {
+	return AnalyzeOutput(o.GetValues(), confidence)
+}
+
+// Print truncation point and batch means confidence interval
+func (o *Observations) PrintReport(label string) {
+	a, err := o.Analyze(DefaultConfidence)
+	if err != nil {
+		fmt.Printf("%s insufficient data for batch means: %d observations\n", label, o.GetCount())
+		return
+	}
+	fmt.Printf("%s warm-up truncation %d of %d\tbatch means %.2f +- %.2f (%g%%, %d batches of %d)\n",
+		label, a.Truncation, a.Count, a.Mean, a.HalfWidth, 100*a.Confidence, a.Batches, a.BatchSize)
+}
+
+// Get count of observations for deleting as warm-up by MSER-5 heuristic:
+// truncation point minimizes standard error of mean of remaining batch means
+// of 5 observations. Only first half of observations can be deleted.
+func MSER5(values []float64) int {
+	k := len(values) / mserBatchSize
+	if k < 2 {
+		return 0
+	}
+	means := make([]float64, k)
+	for i := range means {
+		for _, v := range values[i*mserBatchSize : (i+1)*mserBatchSize] {
+			means[i] += v
+		}
+		means[i] /= mserBatchSize
+	}
+	// Sums of remaining batch means are accumulated from the end
+	var sum, sumSquares float64
+	best, bestD := math.Inf(1), 0
+	for d := k - 1; d >= 0; d-- {
+		sum += means[d]
+		sumSquares += means[d] * means[d]
+		if d > k/2 {
+			continue
+		}
+		n := float64(k - d)
+		mser := (sumSquares - sum*sum/n) / (n * n)
+		if mser <= best {
+			best, bestD = mser, d
+		}
+	}
+	return bestD * mserBatchSize
+}
+
+// Get batch means confidence interval for mean of values. Values are divided
+// to batches of equal size, first values which don't fill batch are skipped.
+// At least MinBatches batches of MinBatchSize values are required.
+func BatchMeans(values []float64, batches int, confidence float64) (OutputAnalysis, error) {
+	if batches < MinBatches {
+		return OutputAnalysis{}, fmt.Errorf("batch means: at least %d batches are required, got %d",
+			MinBatches, batches)
+	}
+	count := len(values)
+	size := count / batches
+	if size < MinBatchSize {
+		return OutputAnalysis{}, fmt.Errorf("batch means: insufficient data, %d observations for %d batches of %d",
+			count, batches, MinBatchSize)
+	}
+	values = values[count-size*batches:]
+	means := make([]float64, batches)
+	for i := range means {
+		for _, v := range values[i*size : (i+1)*size] {
+			means[i] += v
+		}
+		means[i] /= float64(size)
+	}
+	mean, variance := meanVariance(means)
+	a := OutputAnalysis{Count: count, Mean: mean, Batches: batches, BatchSize: size,
+		Confidence: confidence}
+	a.HalfWidth = StudentTQuantile(1-(1-confidence)/2, float64(batches-1)) *
+		math.Sqrt(variance/float64(batches))
+	a.Lower, a.Upper = mean-a.HalfWidth, mean+a.HalfWidth
+	if variance > 0 {
+		var cov float64
+		for i := 1; i < batches; i++ {
+			cov += (means[i] - mean) * (means[i-1] - mean)
+		}
+		a.Lag1 = cov / (variance * float64(batches-1))
+	}
+	return a, nil
+}
+
+// Delete warm-up by MSER-5 and get batch means confidence interval with
+// DefaultBatches batches
+func AnalyzeOutput(values []float64, confidence float64) (OutputAnalysis, error) {
+	truncation := MSER5(values)
+	a, err := BatchMeans(values[truncation:], DefaultBatches, confidence)
+	if err != nil {
+		return a, err
+	}
+	a.Count = len(values)
+	a.Truncation = truncation
+	return a, nil
+}
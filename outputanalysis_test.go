@@ -0,0 +1,90 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMSER5(t *testing.T) {
+	// Warm-up of 50 observations, then steady state
+	values := make([]float64, 1000)
+	for i := range values {
+		if i < 50 {
+			values[i] = float64(100 - i)
+		} else {
+			values[i] = float64(10 + i%2*2)
+		}
+	}
+	if d := MSER5(values); d != 50 {
+		t.Error("Truncation, expected", 50, "got", d)
+	}
+	if d := MSER5(values[:7]); d != 0 {
+		t.Error("Truncation of 7 observations, expected", 0, "got", d)
+	}
+	a, err := AnalyzeOutput(values, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Truncation != 50 || a.Count != 1000 || math.Abs(a.Mean-11) > 0.1 || a.Lower > 11 || a.Upper < 11 {
+		t.Error("Analysis, expected truncation 50 and mean 11, got", a)
+	}
+}
+
+func TestBatchMeans(t *testing.T) {
+	// Batches of 10 values 0..9, 10..19 and so on
+	values := make([]float64, 105)
+	for i := range values {
+		values[i] = float64(i - 5)
+	}
+	a, err := BatchMeans(values, 10, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// First 5 values are skipped, batch means 4.5, 14.5, ..., 94.5
+	if a.Mean != 49.5 || a.BatchSize != 10 || a.Batches != 10 {
+		t.Error("Batch means, expected 49.5 with 10 batches of 10, got", a)
+	}
+	if _, err := BatchMeans(values[:99], 10, 0.95); err == nil {
+		t.Error("Batch means with batches of 9, expected error")
+	}
+	if _, err := BatchMeans(values, 5, 0.95); err == nil {
+		t.Error("Batch means with 5 batches, expected error")
+	}
+}
+
+func TestObservations_HoleAndQueue(t *testing.T) {
+	pipe := NewPipeline("Barbershop", false)
+	g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 16, 4)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	h.EnableSeries()
+	q.EnableSeries()
+	if err := pipe.Run(20000); err != nil {
+		t.Fatal(err)
+	}
+	if n := h.GetLifeSeries().GetCount(); n != h.GetLifeStat().GetCount() || n == 0 {
+		t.Error("Lifes, expected", h.GetLifeStat().GetCount(), "got", n)
+	}
+	if n := q.GetTimeSeries().GetCount(); n != q.GetTimeStat().GetCount() {
+		t.Error("Times in queue, expected", q.GetTimeStat().GetCount(), "got", n)
+	}
+	if NewHole("Disabled").GetLifeSeries() != nil {
+		t.Error("Series, expected to be disabled by default")
+	}
+	a, err := h.GetLifeSeries().Analyze(0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Lower > a.Mean || a.Upper < a.Mean || a.Truncation > a.Count/2 {
+		t.Error("Analysis of lifes, got", a)
+	}
+}
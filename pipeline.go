@@ -6,11 +6,11 @@ package gpss
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
 	"reflect"
 	"sort"
 	"sync"
+	"time"
 )
 
 type IPipeline interface {
@@ -18,7 +18,7 @@ type IPipeline interface {
 	AppendMultiple(obj []IBaseObj, dst ...IBaseObj) // Append  multiple objects to pipeline
 	AppendISlice(obj IBaseObj, dst []IBaseObj)      // Append slice IBaseObj
 	Delete(obj IBaseObj)                            // Delete object from pipeline
-	Start(value int)                                // Start simulation
+	Start(value int) error                          // Start simulation
 	Stop()                                          // Stop simulation
 	GetSimTime() int                                // Get Simulation time
 	GetModelTime() int                              // Get current model time
@@ -36,6 +36,33 @@ type Pipeline struct {
 	simTime   int                 // Simulation time
 	logger    *Logger             // Pipeline logger
 	id        int                 // ID of new transaction
+	stopOnce  sync.Once           // For closing Done only once
+	seed      int64               // Seed of random streams of objects
+	// Interval of model time for saving checkpoints, 0 - checkpoints disabled
+	checkpointInterval int
+	// Path to checkpoint file
+	checkpointPath string
+	// Names of objects overwritten by appending another object with same name
+	duplicates []string
+	stall      stallDetector // Detector of stalled transacts
+	journal    bool          // Journal of transacts is enabled
+	observers  []IObserver   // Observers of events
+	// Counters of objects by names
+	counters map[string]*blockCounters
+	// Tables by names
+	tables map[string]*Table
+	// Names of tables in order of appending
+	tableNames []string
+	// Held while step of simulation, for consistent reading of state
+	mu sync.Mutex
+	// For pausing of simulation started by Start
+	pauseMu sync.Mutex
+	// Closed on resuming, nil if simulation is not paused
+	resume chan struct{}
+	// Pacing in real-time mode
+	pace pacer
+	// For notifying observers about stop only once
+	notifyOnce sync.Once
 }
 
 // Create new Pipeline
@@ -46,36 +73,37 @@ func NewPipeline(name string, verbose bool) *Pipeline {
 	p.Done = make(chan struct{})
 	p.modelTime = 0
 	p.id = 0
-	if !verbose {
-		p.logger = NewLogger(ioutil.Discard, os.Stdout, os.Stdout, os.Stderr)
-	} else {
-		p.logger = NewLogger(os.Stdout, os.Stdout, os.Stdout, os.Stderr)
-	}
+	p.seed = time.Now().UnixNano()
+	p.logger = NewLogger(os.Stdout, os.Stdout, os.Stdout, os.Stderr)
+	p.logger.SetTraceEnabled(verbose)
 	return p
 }
 
 // Append object to pipeline. Src is multiple sources of transact for appended
 // object.
 func (p *Pipeline) Append(obj IBaseObj, dst ...IBaseObj) {
-	obj.SetDst(dst)
-	obj.SetPipeline(p)
-	obj.SetID(len(p.objects))
-	p.objects[obj.GetName()] = obj
+	p.appendObj(obj, dst)
 }
 
 // Append multiple objects to pipeline.  Src is multiple sources of transact
 // for appended object.
 func (p *Pipeline) AppendMultiple(obj []IBaseObj, dst ...IBaseObj) {
 	for _, o := range obj {
-		o.SetDst(dst)
-		o.SetPipeline(p)
-		o.SetID(len(p.objects))
-		p.objects[o.GetName()] = o
+		p.appendObj(o, dst)
 	}
 }
 
 func (p *Pipeline) AppendISlice(obj IBaseObj, dst []IBaseObj) {
+	p.appendObj(obj, dst)
+}
+
+func (p *Pipeline) appendObj(obj IBaseObj, dst []IBaseObj) {
+	if o, ok := p.objects[obj.GetName()]; ok && o != obj {
+		// Object is overwritten, remember it for validation
+		p.duplicates = append(p.duplicates, obj.GetName())
+	}
 	obj.SetDst(dst)
+	p.getCounters(obj.GetName())
 	obj.SetPipeline(p)
 	obj.SetID(len(p.objects))
 	p.objects[obj.GetName()] = obj
@@ -99,52 +127,174 @@ func (p *Pipeline) PrintObjects() {
 	}
 }
 
-// Start simulation
-func (p *Pipeline) Start(value int) {
-	var wg sync.WaitGroup
-
+// Start simulation. Pipeline is validated before start, warnings are printed
+// to log and simulation is not started if errors are found or simulation time
+// is not positive.
+func (p *Pipeline) Start(value int) error {
+	if value <= 0 {
+		return fmt.Errorf("simulation time must be positive, got %d", value)
+	}
+	problems := p.Validate()
+	for _, v := range problems.Warnings() {
+		p.logger.Warning.Println(v)
+	}
+	if errs := problems.Errors(); len(errs) > 0 {
+		return errs
+	}
 	p.simTime = value
+	p.resetPace()
 	go func() {
 		for {
 			select {
 			case <-p.Done:
 				return
 			default:
-				p.logger.Trace.Println("ModelTime ", p.modelTime)
-				wg.Add(len(p.objects))
-				for _, o := range p.objects {
-					o.HandleTransacts(&wg)
+				if resume := p.getResume(); resume != nil {
+					select {
+					case <-resume:
+					case <-p.Done:
+					}
+					p.resetPace()
+					continue
 				}
-				wg.Wait()
-				if p.modelTime++; p.modelTime == value {
-					p.Stop()
+				p.waitPace()
+				if p.IsStopped() {
+					return
 				}
+				p.Step()
 			}
 		}
 	}()
+	return nil
+}
+
+// Start simulation and wait for its end
+func (p *Pipeline) Run(value int) error {
+	if err := p.Start(value); err != nil {
+		return err
+	}
+	<-p.Done
+	// Wait for end of last step
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.notifyStop()
+	return nil
+}
+
+// Make one step of simulation: objects handle their transacts one by one in
+// order of appending, so runs with the same seed are repeated exactly, and
+// model time is incremented. Simulation is stopped when model time reaches
+// simulation time. Simulation with not positive simulation time is stopped
+// without step.
+func (p *Pipeline) Step() {
+	var wg sync.WaitGroup
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.simTime <= 0 {
+		p.logger.Error.Println("Simulation time must be positive, got", p.simTime)
+		p.Stop()
+		p.notifyStop()
+		return
+	}
+
+	for _, o := range p.GetSortedObjects() {
+		wg.Add(1)
+		o.HandleTransacts(&wg)
+		wg.Wait()
+	}
+	for _, o := range p.observers {
+		o.OnTick(p.modelTime)
+	}
+	p.detectStall()
+	p.modelTime++
+	if p.checkpointInterval > 0 && p.modelTime%p.checkpointInterval == 0 {
+		if err := p.SaveCheckpointFile(p.checkpointPath); err != nil {
+			p.logger.Error.Println("Save checkpoint:", err)
+		}
+	}
+	if p.modelTime >= p.simTime {
+		p.Stop()
+	}
+	if p.IsStopped() {
+		p.notifyStop()
+	}
 }
 
-// Stop simulation
+// Stop simulation. Observers are notified after end of current step, so they
+// don't receive events after OnStop.
 func (p *Pipeline) Stop() {
-	close(p.Done)
+	p.stopOnce.Do(func() {
+		close(p.Done)
+		go func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.notifyStop()
+		}()
+	})
+}
+
+// Notify observers about stop of simulation, p.mu must be held
+func (p *Pipeline) notifyStop() {
+	p.notifyOnce.Do(func() {
+		for _, o := range p.observers {
+			o.OnStop(p.modelTime)
+		}
+	})
+}
+
+// Pause simulation started by Start, current step is finished
+func (p *Pipeline) Pause() {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+	if p.resume == nil {
+		p.resume = make(chan struct{})
+	}
+}
+
+// Resume paused simulation
+func (p *Pipeline) Resume() {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+	if p.resume != nil {
+		close(p.resume)
+		p.resume = nil
+	}
+}
+
+// Is simulation paused?
+func (p *Pipeline) IsPaused() bool {
+	return p.getResume() != nil
+}
+
+func (p *Pipeline) getResume() chan struct{} {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+	return p.resume
+}
+
+// Is simulation stopped?
+func (p *Pipeline) IsStopped() bool {
+	select {
+	case <-p.Done:
+		return true
+	default:
+		return false
+	}
 }
 
 // Print report about work of pipeline
 func (p *Pipeline) PrintReport() {
 	fmt.Println("Pipeline name \"", p.name, "\"")
 	fmt.Println("Simulation time", p.modelTime)
-	sortedObjects := make([]IBaseObj, 0, len(p.objects))
-	for _, v := range p.objects {
-		sortedObjects = append(sortedObjects, v)
+	for _, v := range p.GetSortedObjects() {
+		v.PrintReport()
 	}
-
-	id := func(p1, p2 IBaseObj) bool {
-		return p1.GetID() < p2.GetID()
+	for _, t := range p.GetTables() {
+		t.PrintReport()
 	}
-
-	By(id).Sort(sortedObjects)
-	for _, v := range sortedObjects {
-		v.PrintReport()
+	if p.stall.report != nil {
+		fmt.Println(p.stall.report)
 	}
 
 }
@@ -154,6 +304,12 @@ func (p *Pipeline) GetSimTime() int {
 	return p.simTime
 }
 
+// Set value of simulation time without starting simulation, for step by step
+// simulation
+func (p *Pipeline) SetSimTime(value int) {
+	p.simTime = value
+}
+
 // Get current model time
 func (p *Pipeline) GetModelTime() int {
 	return p.modelTime
@@ -164,11 +320,44 @@ func (p *Pipeline) GetLogger() ILogger {
 	return p.logger
 }
 
+// Set seed of random streams of objects. Must be called before start of
+// simulation.
+func (p *Pipeline) SetSeed(seed int64) {
+	p.seed = seed
+}
+
+// Get seed of random streams of objects
+func (p *Pipeline) GetSeed() int64 {
+	return p.seed
+}
+
+// Save checkpoint to file at path every interval of model time, 0 disables
+// saving
+func (p *Pipeline) SetCheckpoint(interval int, path string) {
+	p.checkpointInterval = interval
+	p.checkpointPath = path
+}
+
 // Get object from pipeline by name
 func (p *Pipeline) GetObjByName(name string) IBaseObj {
 	return p.objects[name]
 }
 
+// Get objects of pipeline sorted by ID
+func (p *Pipeline) GetSortedObjects() []IBaseObj {
+	sortedObjects := make([]IBaseObj, 0, len(p.objects))
+	for _, v := range p.objects {
+		sortedObjects = append(sortedObjects, v)
+	}
+
+	id := func(p1, p2 IBaseObj) bool {
+		return p1.GetID() < p2.GetID()
+	}
+
+	By(id).Sort(sortedObjects)
+	return sortedObjects
+}
+
 type By func(p1, p2 IBaseObj) bool
 
 func (by By) Sort(objects []IBaseObj) {
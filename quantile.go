@@ -0,0 +1,221 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Default relative accuracy of quantiles
+const DefaultQuantileAccuracy = 0.01
+
+// Default max count of buckets of quantile sketch
+const DefaultQuantileBuckets = 2048
+
+// Streaming sketch of quantiles with bounded memory. Values are counted in
+// buckets with logarithmic bounds, so quantile is estimated with relative
+// accuracy. If count of buckets exceeds the limit, lowest buckets are merged,
+// so accuracy of high quantiles is kept. Sketch is intended for non-negative
+// values like times, negative values are counted as zero.
+type QuantileSketch struct {
+	mu         sync.Mutex
+	gamma      float64     // Ratio of bounds of bucket
+	logGamma   float64     // Logarithm of gamma
+	maxBuckets int         // Max count of buckets
+	buckets    map[int]int // Counts of values by indexes of buckets
+	zeros      int         // Count of zero values
+	count      int         // Count of all values
+	sum        float64     // Sum of values
+	min        float64     // Min value
+	max        float64     // Max value
+}
+
+// Creates new QuantileSketch.
+// accuracy - relative accuracy of quantiles, for example 0.01;
+// maxBuckets - max count of buckets, bounds used memory
+func NewQuantileSketch(accuracy float64, maxBuckets int) *QuantileSketch {
+	if accuracy <= 0 || accuracy >= 1 {
+		accuracy = DefaultQuantileAccuracy
+	}
+	if maxBuckets < 1 {
+		maxBuckets = DefaultQuantileBuckets
+	}
+	gamma := (1 + accuracy) / (1 - accuracy)
+	return &QuantileSketch{gamma: gamma, logGamma: math.Log(gamma), maxBuckets: maxBuckets,
+		buckets: make(map[int]int)}
+}
+
+// Add value to sketch
+func (s *QuantileSketch) Add(value float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value < 0 {
+		value = 0
+	}
+	if s.count == 0 || value < s.min {
+		s.min = value
+	}
+	if s.count == 0 || value > s.max {
+		s.max = value
+	}
+	s.count++
+	s.sum += value
+	if value == 0 {
+		s.zeros++
+		return
+	}
+	s.buckets[int(math.Ceil(math.Log(value)/s.logGamma))]++
+	if len(s.buckets) > s.maxBuckets {
+		s.collapse()
+	}
+}
+
+// Merge two lowest buckets, must be called under lock
+func (s *QuantileSketch) collapse() {
+	indexes := s.sortedIndexes()
+	s.buckets[indexes[1]] += s.buckets[indexes[0]]
+	delete(s.buckets, indexes[0])
+}
+
+func (s *QuantileSketch) sortedIndexes() []int {
+	indexes := make([]int, 0, len(s.buckets))
+	for i := range s.buckets {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// Get estimation of quantile q from 0 to 1, 0 if sketch is empty
+func (s *QuantileSketch) GetQuantile(q float64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return s.min
+	}
+	if q >= 1 {
+		return s.max
+	}
+	rank := int(q * float64(s.count-1))
+	if rank < s.zeros {
+		return 0
+	}
+	seen := s.zeros
+	for _, i := range s.sortedIndexes() {
+		seen += s.buckets[i]
+		if seen > rank {
+			value := 2 * math.Pow(s.gamma, float64(i)) / (s.gamma + 1)
+			return math.Max(s.min, math.Min(value, s.max))
+		}
+	}
+	return s.max
+}
+
+// Get count of values
+func (s *QuantileSketch) GetCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Get mean of values
+func (s *QuantileSketch) GetMean() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 {
+		return 0
+	}
+	return s.sum / float64(s.count)
+}
+
+// Get min value
+func (s *QuantileSketch) GetMin() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.min
+}
+
+// Get max value
+func (s *QuantileSketch) GetMax() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.max
+}
+
+// Reset all values of sketch
+func (s *QuantileSketch) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buckets = make(map[int]int)
+	s.zeros, s.count, s.sum, s.min, s.max = 0, 0, 0, 0, 0
+}
+
+// Get table of frequency classes estimated by sketch, for histogram when
+// table is not set. Classes have same width from 0 to max value, values of
+// bucket are counted in class of its middle.
+// name - name of table; count - count of frequency classes
+func (s *QuantileSketch) GetTable(name string, count int) *Table {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if count < 2 {
+		count = 2
+	}
+	width := s.max / float64(count-1)
+	if width <= 0 {
+		width = 1
+	}
+	table, _ := NewTable(name, 0, width, count)
+	table.addCount(0, s.zeros)
+	for _, i := range s.sortedIndexes() {
+		value := 2 * math.Pow(s.gamma, float64(i)) / (s.gamma + 1)
+		table.addCount(math.Max(s.min, math.Min(value, s.max)), s.buckets[i])
+	}
+	return table
+}
+
+// Print p50, p90, p95, p99 and max of values
+func (s *QuantileSketch) PrintReport(label string) {
+	if s.GetCount() == 0 {
+		return
+	}
+	fmt.Printf("%s p50 %.2f\tp90 %.2f\tp95 %.2f\tp99 %.2f\tmax %.2f\n", label, s.GetQuantile(0.5),
+		s.GetQuantile(0.9), s.GetQuantile(0.95), s.GetQuantile(0.99), s.GetMax())
+}
+
+type quantileSketchState struct {
+	Buckets map[int]int
+	Zeros   int
+	Count   int
+	Sum     float64
+	Min     float64
+	Max     float64
+}
+
+func (s *QuantileSketch) getState() quantileSketchState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buckets := make(map[int]int, len(s.buckets))
+	for i, c := range s.buckets {
+		buckets[i] = c
+	}
+	return quantileSketchState{Buckets: buckets, Zeros: s.zeros, Count: s.count, Sum: s.sum,
+		Min: s.min, Max: s.max}
+}
+
+func (s *QuantileSketch) setState(state quantileSketchState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buckets = state.Buckets
+	if s.buckets == nil {
+		s.buckets = make(map[int]int)
+	}
+	s.zeros, s.count, s.sum, s.min, s.max = state.Zeros, state.Count, state.Sum, state.Min, state.Max
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuantileSketch(t *testing.T) {
+	s := NewQuantileSketch(0.01, 2048)
+	for i := 0; i <= 100000; i++ {
+		s.Add(float64(i))
+	}
+	for _, q := range []float64{0.5, 0.9, 0.95, 0.99} {
+		expected := q * 100000
+		if v := s.GetQuantile(q); math.Abs(v-expected) > 0.01*expected {
+			t.Error("Quantile", q, "expected", expected, "got", v)
+		}
+	}
+	if s.GetMax() != 100000 || s.GetCount() != 100001 || s.GetQuantile(0) != 0 {
+		t.Error("Max, count, min, expected 100000 100001 0, got", s.GetMax(), s.GetCount(), s.GetQuantile(0))
+	}
+
+	// Memory is bounded, high quantiles are kept
+	small := NewQuantileSketch(0.01, 100)
+	for i := 1; i <= 100000; i++ {
+		small.Add(float64(i))
+	}
+	if len(small.buckets) > 100 {
+		t.Error("Buckets, expected not more", 100, "got", len(small.buckets))
+	}
+	if v := small.GetQuantile(0.99); math.Abs(v-99000) > 990 {
+		t.Error("Quantile 0.99 of small sketch, expected", 99000, "got", v)
+	}
+}
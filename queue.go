@@ -17,11 +17,13 @@ type IQueue interface {
 // Queue of transaction
 type Queue struct {
 	BaseObj
-	sum_timequeue   float64 // Sum all transact queue time
-	sum_zeroEntries float64 // Sum zero entrise
-	sum_Entries     float64 // Sum all entries
-	max_content     int     // Max content in queue
-	sum_content     float64 // Sum content in queue
+	sum_timequeue   float64         // Sum all transact queue time
+	sum_zeroEntries float64         // Sum zero entrise
+	sum_Entries     float64         // Sum all entries
+	content         *TimeWeighted   // Content of queue
+	qtable          *Table          // Table of times in queue, nil if not set
+	times           *QuantileSketch // Quantiles of times in queue
+	timeSeries      *Observations   // Times in queue in order of leaving, nil if disabled
 }
 
 // Creates new Queue.
@@ -29,18 +31,19 @@ type Queue struct {
 func NewQueue(name string) *Queue {
 	obj := &Queue{}
 	obj.BaseObj.Init(name)
+	obj.content = NewTimeWeighted(0, 0)
+	obj.times = NewQuantileSketch(DefaultQuantileAccuracy, DefaultQuantileBuckets)
 	return obj
 }
 
 func (obj *Queue) HandleTransact(transact ITransaction) {
 	transact.InqQueueTime()
-	transact.PrintInfo()
 }
 
 // Check that after queue exist empty object
 func (obj *Queue) IsObjectAfterMeEmpty(transact ITransaction) bool {
 	for _, o := range obj.GetDst() {
-		if o.AppendTransact(transact) {
+		if SendTransact(obj, o, transact) {
 			return true
 		}
 	}
@@ -52,6 +55,51 @@ func (obj *Queue) GetLength() int {
 	return obj.tb.GetLen()
 }
 
+// Set table of times in queue (GPSS QTABLE), zero entries are tabulated too
+func (obj *Queue) SetQTable(table *Table) {
+	obj.qtable = table
+}
+
+// Get table of times in queue, nil if not set
+func (obj *Queue) GetQTable() *Table {
+	return obj.qtable
+}
+
+// Add time in queue to table, quantiles and series of times
+func (obj *Queue) tabulate(queueTime float64) {
+	obj.times.Add(queueTime)
+	if obj.timeSeries != nil {
+		obj.timeSeries.Add(queueTime)
+	}
+	if obj.qtable != nil {
+		obj.qtable.Add(queueTime)
+	}
+}
+
+// Get quantiles of times in queue
+func (obj *Queue) GetTimeStat() *QuantileSketch {
+	return obj.times
+}
+
+// Enable recording of times in queue in order of leaving, for output
+// analysis. Series keeps all times, so it must be enabled only when it is
+// needed. It must be called before start of simulation.
+func (obj *Queue) EnableSeries() {
+	if obj.timeSeries == nil {
+		obj.timeSeries = NewObservations()
+	}
+}
+
+// Get times in queue in order of leaving, nil if recording is not enabled
+func (obj *Queue) GetTimeSeries() *Observations {
+	return obj.timeSeries
+}
+
+// Get time-weighted statistics of content of queue
+func (obj *Queue) GetContentStat() *TimeWeighted {
+	return obj.content
+}
+
 func (obj *Queue) HandleTransacts(wg *sync.WaitGroup) {
 	go func() {
 		defer wg.Done()
@@ -61,33 +109,32 @@ func (obj *Queue) HandleTransacts(wg *sync.WaitGroup) {
 			for tr != nil {
 				if obj.IsObjectAfterMeEmpty(tr.transact) {
 					obj.sum_timequeue += float64(tr.transact.GetQueueTime())
+					obj.tabulate(float64(tr.transact.GetQueueTime()))
 					obj.tb.Pop()
+					obj.content.Add(obj.GetPipeline().GetModelTime(), -1)
 					tr = obj.tb.GetFirstItem()
 				} else {
 					break
 				}
 			}
 		}
-		transacts := obj.tb.GetItems()
+		transacts := getStepItems(obj.tb)
 		for _, tr := range transacts {
 			obj.HandleTransact(tr.transact)
 		}
-		obj.sum_content += float64(obj.tb.GetLen())
 	}()
 }
 
 func (obj *Queue) AppendTransact(transact ITransaction) bool {
-	obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Queue")
 	transact.SetHolderName(obj.name)
 	if !obj.IsObjectAfterMeEmpty(transact) {
 		transact.ResetQueueTime()
 		obj.tb.Push(transact)
 		transact.InqQueueTime()
-		if obj.max_content < obj.tb.GetLen() {
-			obj.max_content = obj.tb.GetLen()
-		}
+		obj.content.Add(obj.GetPipeline().GetModelTime(), 1)
 	} else {
 		obj.sum_zeroEntries++
+		obj.tabulate(0)
 	}
 	obj.sum_Entries++
 	return true
@@ -95,12 +142,56 @@ func (obj *Queue) AppendTransact(transact ITransaction) bool {
 
 func (obj *Queue) PrintReport() {
 	obj.BaseObj.PrintReport()
-	fmt.Printf("Max content %d\tTotal entries %2.f\tZero entries %2.f\tPersent zero entries %.2f%%\n",
-		obj.max_content, obj.sum_Entries, obj.sum_zeroEntries, 100*obj.sum_zeroEntries/obj.sum_Entries)
+	modelTime := obj.GetPipeline().GetModelTime()
+	fmt.Printf("Max content %.f\tTotal entries %2.f\tZero entries %2.f\tPersent zero entries %.2f%%\n",
+		obj.content.GetMax(), obj.sum_Entries, obj.sum_zeroEntries, 100*obj.sum_zeroEntries/obj.sum_Entries)
 	fmt.Printf("Current contents %d\tAverage content %.2f\tAverage time/trans %.2f\n", obj.tb.GetLen(),
-		obj.sum_content/float64(obj.GetPipeline().GetSimTime()), obj.sum_timequeue/obj.sum_Entries)
+		obj.content.GetMean(modelTime), obj.sum_timequeue/obj.sum_Entries)
 	if obj.sum_Entries-obj.sum_zeroEntries > 0 {
 		fmt.Printf("Average time/trans without zero entries %.2f\n", obj.sum_timequeue/(obj.sum_Entries-obj.sum_zeroEntries))
 	}
+	obj.times.PrintReport("Time in queue")
+	if obj.timeSeries != nil {
+		obj.timeSeries.PrintReport("Time in queue")
+	}
 	fmt.Println()
 }
+
+type queueState struct {
+	SumTimeQueue   float64
+	SumZeroEntries float64
+	SumEntries     float64
+	Content        timeWeightedState
+	Times          quantileSketchState
+	TimeSeries     []float64
+}
+
+func (obj *Queue) SaveState() ([]byte, error) {
+	state := queueState{
+		SumTimeQueue:   obj.sum_timequeue,
+		SumZeroEntries: obj.sum_zeroEntries,
+		SumEntries:     obj.sum_Entries,
+		Content:        obj.content.getState(),
+		Times:          obj.times.getState(),
+	}
+	if obj.timeSeries != nil {
+		state.TimeSeries = obj.timeSeries.GetValues()
+	}
+	return encodeState(state)
+}
+
+func (obj *Queue) LoadState(data []byte) error {
+	state := queueState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	obj.sum_timequeue = state.SumTimeQueue
+	obj.sum_zeroEntries = state.SumZeroEntries
+	obj.sum_Entries = state.SumEntries
+	obj.content.setState(state.Content)
+	obj.times.setState(state.Times)
+	if obj.timeSeries != nil {
+		obj.timeSeries.setValues(state.TimeSeries)
+	}
+	return nil
+}
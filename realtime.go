@@ -0,0 +1,69 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"time"
+)
+
+// Pacing of simulation by wall-clock time
+type pacer struct {
+	scale     time.Duration // Wall-clock duration of unit of model time, 0 - pacing disabled
+	reset     bool          // Base of pacing must be reset before next step
+	base      time.Time     // Wall-clock time of base model time
+	baseModel int           // Base model time
+}
+
+// Set real-time mode of simulation started by Start: unit of model time lasts
+// scale of wall-clock time, for example time.Minute/600 makes model minute
+// last 100 ms. Scale 0 disables real-time mode, simulation runs as fast as
+// possible. Steps are scheduled by deadlines, so slow steps don't accumulate
+// delay. Scale can be changed while simulation is running.
+func (p *Pipeline) SetRealTime(scale time.Duration) {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+	p.pace.scale = scale
+	p.pace.reset = true
+}
+
+// Get wall-clock duration of unit of model time, 0 if real-time mode is
+// disabled
+func (p *Pipeline) GetRealTime() time.Duration {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+	return p.pace.scale
+}
+
+// Reset base of pacing after start, pause or change of scale
+func (p *Pipeline) resetPace() {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+	p.pace.reset = true
+}
+
+// Wait for wall-clock time of next step in real-time mode
+func (p *Pipeline) waitPace() {
+	p.pauseMu.Lock()
+	if p.pace.reset {
+		p.pace.base = time.Now()
+		p.pace.baseModel = p.modelTime
+		p.pace.reset = false
+	}
+	scale, base, baseModel := p.pace.scale, p.pace.base, p.pace.baseModel
+	p.pauseMu.Unlock()
+	if scale <= 0 {
+		return
+	}
+	wait := time.Until(base.Add(time.Duration(p.modelTime-baseModel) * scale))
+	if wait <= 0 {
+		return
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-p.Done:
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPipeline_SetRealTime(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 2, 0, 0, 0, nil)
+	h := NewHole("Out")
+	pipe.Append(g, h)
+	pipe.Append(h)
+	scale := 5 * time.Millisecond
+	pipe.SetRealTime(scale)
+	if pipe.GetRealTime() != scale {
+		t.Error("Real time, expected", scale, "got", pipe.GetRealTime())
+	}
+
+	start := time.Now()
+	if err := pipe.Start(20); err != nil {
+		t.Fatal(err)
+	}
+	<-pipe.Done
+	// Last step is made at model time 19
+	if elapsed := time.Since(start); elapsed < 19*scale {
+		t.Error("Duration of simulation, expected at least", 19*scale, "got", elapsed)
+	}
+
+	fast := NewPipeline("pipe", false)
+	out := NewHole("Out")
+	fast.Append(NewGenerator("Clients", 2, 0, 0, 0, nil), out)
+	fast.Append(out)
+	fast.SetRealTime(time.Hour)
+	fast.SetRealTime(0)
+	start = time.Now()
+	if err := fast.Start(20); err != nil {
+		t.Fatal(err)
+	}
+	<-fast.Done
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Error("Duration without real-time mode, expected less than second, got", elapsed)
+	}
+}
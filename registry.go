@@ -0,0 +1,127 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"sort"
+)
+
+// Information about live transact
+type TransactInfo struct {
+	ID           int                    `json:"id"`             // Transact ID
+	HolderName   string                 `json:"holder"`         // Object where transact is
+	TimeInHolder int                    `json:"time_in_holder"` // Time in current holder
+	QueueTime    int                    `json:"queue_time"`     // Time in queue at this moment
+	Part         int                    `json:"part"`           // Part id, for splitted transact
+	Parts        int                    `json:"parts"`          // Number of parts, for splitted transact
+	ParentID     int                    `json:"parent_id"`      // ID of parent transact, for splitted transact
+	Parameters   map[string]interface{} `json:"parameters"`     // Copy of parameters of transact
+	Transact     ITransaction           `json:"-"`              // Transact
+}
+
+func newTransactInfo(transact ITransaction) TransactInfo {
+	info := TransactInfo{
+		ID:           transact.GetId(),
+		HolderName:   transact.GetHolderName(),
+		TimeInHolder: transact.GetTimeInHolder(),
+		QueueTime:    transact.GetQueueTime(),
+		Parameters:   make(map[string]interface{}),
+		Transact:     transact,
+	}
+	info.Part, info.Parts, info.ParentID = transact.GetParts()
+	for k, v := range transact.GetAllParameters() {
+		info.Parameters[k] = v
+	}
+	return info
+}
+
+// Get all live transacts from transact tables of objects. Each transact is
+// returned once, even if it is kept in several tables.
+func (p *Pipeline) getLiveTransacts() []ITransaction {
+	return p.collectTransacts(false)
+}
+
+// Get transacts from transact tables of objects, killed transacts are kept
+// in Holes if journal is enabled
+func (p *Pipeline) collectTransacts(withKilled bool) []ITransaction {
+	var transacts []ITransaction
+	seen := make(map[ITransaction]bool)
+	for _, o := range p.GetSortedObjects() {
+		tabler, ok := o.(ITransactTabler)
+		if !ok || tabler.GetTransactTable() == nil {
+			continue
+		}
+		for _, item := range tabler.GetTransactTable().GetItems() {
+			if seen[item.transact] || (!withKilled && item.transact.IsKilled()) {
+				continue
+			}
+			seen[item.transact] = true
+			transacts = append(transacts, item.transact)
+		}
+	}
+	sort.Slice(transacts, func(i, j int) bool {
+		return transacts[i].GetId() < transacts[j].GetId()
+	})
+	return transacts
+}
+
+// Get transact by ID from transact tables of objects. Live transact is
+// preferred to killed one.
+func (p *Pipeline) GetTransactByID(id int) ITransaction {
+	var found ITransaction
+	for _, o := range p.GetSortedObjects() {
+		tabler, ok := o.(ITransactTabler)
+		if !ok || tabler.GetTransactTable() == nil {
+			continue
+		}
+		item := tabler.GetTransactTable().GetItem(id)
+		if item == nil {
+			continue
+		}
+		if !item.transact.IsKilled() {
+			return item.transact
+		}
+		found = item.transact
+	}
+	return found
+}
+
+// Find live transacts which satisfy to filter
+func (p *Pipeline) findTransacts(filter func(transact ITransaction) bool) []TransactInfo {
+	var infos []TransactInfo
+	for _, t := range p.getLiveTransacts() {
+		if filter(t) {
+			infos = append(infos, newTransactInfo(t))
+		}
+	}
+	return infos
+}
+
+// Get all live transacts, sorted by ID
+func (p *Pipeline) GetTransacts() []TransactInfo {
+	return p.findTransacts(func(ITransaction) bool { return true })
+}
+
+// Get live transacts which are in object with name
+func (p *Pipeline) GetTransactsInObj(name string) []TransactInfo {
+	return p.findTransacts(func(t ITransaction) bool {
+		return t.GetHolderName() == name
+	})
+}
+
+// Find live transacts which have parameter with name equal to value
+func (p *Pipeline) FindTransactsByParameter(name string, value interface{}) []TransactInfo {
+	return p.findTransacts(func(t ITransaction) bool {
+		return t.GetParameterByName(name) == value
+	})
+}
+
+// Find live parts of transact splitted by Split
+func (p *Pipeline) FindTransactsByParent(parentID int) []TransactInfo {
+	return p.findTransacts(func(t ITransaction) bool {
+		_, _, parent_id := t.GetParts()
+		return parent_id == parentID
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"testing"
+)
+
+func TestPipeline_GetTransacts(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	f_in, f_out := NewBifacility("Master")
+	a := NewAdvance("Master work", 16, 0)
+	h := NewHole("Out")
+	pipe.Append(f_in, a)
+	pipe.Append(a, f_out)
+	pipe.Append(f_out, h)
+	pipe.Append(h)
+
+	transact := NewTransaction(pipe.GetIDNewTransaction(), pipe)
+	transact.SetParameters([]Parameter{{Name: "Client", Value: "VIP"}})
+	pipe.modelTime = 5
+	f_in.AppendTransact(transact)
+	pipe.modelTime = 8
+
+	// Transact is kept by bifacility and advance, but it is returned once
+	infos := pipe.GetTransacts()
+	if len(infos) != 1 {
+		t.Fatal("Live transacts, expected", 1, "got", len(infos))
+	}
+	if infos[0].HolderName != "Master work" || infos[0].TimeInHolder != 3 {
+		t.Error("Transact info, expected in \"Master work\" for 3, got", infos[0].HolderName,
+			infos[0].TimeInHolder)
+	}
+	if len(pipe.GetTransactsInObj("Master work")) != 1 {
+		t.Error("Transacts in \"Master work\", expected", 1)
+	}
+	if len(pipe.FindTransactsByParameter("Client", "VIP")) != 1 {
+		t.Error("Transacts with parameter Client=VIP, expected", 1)
+	}
+	if pipe.GetTransactByID(transact.GetId()) != transact {
+		t.Error("Transact by ID, expected", transact.GetId())
+	}
+}
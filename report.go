@@ -0,0 +1,325 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Standard report in GPSS World format
+type StandardReport struct {
+	Name       string            `json:"name"`
+	StartTime  int               `json:"start_time"`
+	EndTime    int               `json:"end_time"`
+	Blocks     []BlockReport     `json:"blocks"`
+	Facilities []FacilityReport  `json:"facilities"`
+	Queues     []QueueReport     `json:"queues"`
+	Savevalues []SavevalueReport `json:"savevalues"`
+	Tables     []TableReport     `json:"tables"`
+	Quantiles  []QuantileReport  `json:"quantiles"`
+}
+
+// Line of block table of standard report
+type BlockReport struct {
+	Name         string `json:"name"`
+	Loc          int    `json:"loc"`
+	Type         string `json:"type"`
+	EntryCount   int    `json:"entry_count"`
+	CurrentCount int    `json:"current_count"`
+	Refusals     int    `json:"refusals"`
+}
+
+// Line of facility table of standard report
+type FacilityReport struct {
+	Name    string  `json:"name"`
+	Entries int     `json:"entries"`
+	Util    float64 `json:"util"`
+	AveTime float64 `json:"ave_time"`
+	Avail   int     `json:"avail"`
+	Owner   int     `json:"owner"`
+	Pend    int     `json:"pend"`
+	Inter   int     `json:"inter"`
+	Delay   int     `json:"delay"`
+}
+
+// Line of queue table of standard report
+type QueueReport struct {
+	Name           string  `json:"name"`
+	Max            int     `json:"max"`
+	Cont           int     `json:"cont"`
+	Entry          int     `json:"entry"`
+	Entry0         int     `json:"entry0"`
+	AveCont        float64 `json:"ave_cont"`
+	AveTime        float64 `json:"ave_time"`
+	AveTimeNonZero float64 `json:"ave_time_non_zero"`
+}
+
+// Line of savevalue table of standard report, values of Counts
+type SavevalueReport struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// Table of standard report
+type TableReport struct {
+	Name    string             `json:"name"`
+	Entries int                `json:"entries"`
+	Mean    float64            `json:"mean"`
+	StdDev  float64            `json:"std_dev"`
+	Classes []TableClassReport `json:"classes"` // Only classes with observed values
+}
+
+// Frequency class of table in standard report
+type TableClassReport struct {
+	From       *float64 `json:"from"` // nil for first class
+	To         *float64 `json:"to"`   // nil for last class
+	Frequency  int      `json:"frequency"`
+	Percent    float64  `json:"percent"`
+	Cumulative float64  `json:"cumulative"`
+}
+
+// Quantiles of times in standard report
+type QuantileReport struct {
+	Name  string  `json:"name"`
+	Kind  string  `json:"kind"` // life, queue time or holding time
+	Count int     `json:"count"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P95   float64 `json:"p95"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+func newQuantileReport(name, kind string, s *QuantileSketch) QuantileReport {
+	return QuantileReport{Name: name, Kind: kind, Count: s.GetCount(), P50: s.GetQuantile(0.5),
+		P90: s.GetQuantile(0.9), P95: s.GetQuantile(0.95), P99: s.GetQuantile(0.99), Max: s.GetMax()}
+}
+
+// Get type of block in GPSS terms
+func getBlockType(obj IBaseObj) string {
+	switch obj.(type) {
+	case *Generator:
+		return "GENERATE"
+	case *Advance:
+		return "ADVANCE"
+	case *Queue:
+		return "QUEUE"
+	case *Facility:
+		return "FACILITY"
+	case *InFacility:
+		return "SEIZE"
+	case *OutFacility:
+		return "RELEASE"
+	case *Split:
+		return "SPLIT"
+	case *Aggregate:
+		return "ASSEMBLE"
+	case *Check:
+		return "TEST"
+	case *Assign:
+		return "ASSIGN"
+	case *Count:
+		return "SAVEVALUE"
+	case *Tabulate:
+		return "TABULATE"
+	case *Hole:
+		return "TERMINATE"
+	}
+	t := reflect.TypeOf(obj)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return strings.ToUpper(t.Name())
+}
+
+// Divide, returns 0 in case of zero divisor
+func safeDiv(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// Get standard report about work of pipeline
+func (p *Pipeline) GetStandardReport() *StandardReport {
+	r := &StandardReport{Name: p.name, EndTime: p.modelTime}
+	counts := make(map[*int]bool)
+	for _, o := range p.GetSortedObjects() {
+		counters := p.GetBlockCounters(o.GetName())
+		r.Blocks = append(r.Blocks, BlockReport{
+			Name:         o.GetName(),
+			Loc:          o.GetID() + 1,
+			Type:         getBlockType(o),
+			EntryCount:   counters.Entries,
+			CurrentCount: counters.Current,
+			Refusals:     counters.Refusals,
+		})
+		switch obj := o.(type) {
+		case *Queue:
+			r.Queues = append(r.Queues, QueueReport{
+				Name:           obj.name,
+				Max:            int(obj.content.GetMax()),
+				Cont:           obj.tb.GetLen(),
+				Entry:          int(obj.sum_Entries),
+				Entry0:         int(obj.sum_zeroEntries),
+				AveCont:        obj.content.GetMean(p.modelTime),
+				AveTime:        safeDiv(obj.sum_timequeue, obj.sum_Entries),
+				AveTimeNonZero: safeDiv(obj.sum_timequeue, obj.sum_Entries-obj.sum_zeroEntries),
+			})
+			r.Quantiles = append(r.Quantiles, newQuantileReport(obj.name, "queue time", obj.times))
+		case *Facility:
+			r.Facilities = append(r.Facilities, p.newFacilityReport(obj, obj.cnt_transact,
+				obj.busy, obj.HoldedTransactID))
+			r.Quantiles = append(r.Quantiles, newQuantileReport(obj.name, "holding time", obj.holding))
+		case *InFacility:
+			r.Facilities = append(r.Facilities, p.newFacilityReport(obj, obj.cnt_transact,
+				obj.busy, obj.HoldedTransactID))
+			r.Quantiles = append(r.Quantiles, newQuantileReport(obj.name, "holding time", obj.holding))
+		case *Hole:
+			r.Quantiles = append(r.Quantiles, newQuantileReport(obj.name, "life", obj.life))
+		case *Count:
+			if !counts[obj.value] {
+				counts[obj.value] = true
+				name := strings.TrimSuffix(strings.TrimSuffix(obj.name, "_INC"), "_DEC")
+				r.Savevalues = append(r.Savevalues, SavevalueReport{Name: name, Value: float64(*obj.value)})
+			}
+		}
+	}
+	for _, t := range p.GetTables() {
+		r.Tables = append(r.Tables, newTableReport(t))
+	}
+	return r
+}
+
+func newTableReport(t *Table) TableReport {
+	tr := TableReport{Name: t.GetName(), Entries: t.GetEntries(), Mean: t.GetMean(), StdDev: t.GetStdDev()}
+	classes := t.GetClasses()
+	for i, c := range classes {
+		if c.Frequency == 0 {
+			continue
+		}
+		cr := TableClassReport{Frequency: c.Frequency, Percent: c.Percent, Cumulative: c.Cumulative}
+		if i > 0 {
+			from := classes[i-1].Upper
+			cr.From = &from
+		}
+		if i < len(classes)-1 {
+			to := c.Upper
+			cr.To = &to
+		}
+		tr.Classes = append(tr.Classes, cr)
+	}
+	return tr
+}
+
+func (p *Pipeline) newFacilityReport(obj IBaseObj, entries float64, busy *TimeWeighted, holded int) FacilityReport {
+	f := FacilityReport{
+		Name:    obj.GetName(),
+		Entries: int(entries),
+		Util:    busy.GetMean(p.modelTime),
+		AveTime: safeDiv(busy.GetArea(p.modelTime), entries),
+		Avail:   1,
+	}
+	if holded > 0 {
+		f.Owner = holded
+	}
+	// Transacts in queues before facility are delayed
+	for _, o := range p.objects {
+		queue, ok := o.(*Queue)
+		if !ok {
+			continue
+		}
+		for _, d := range getAllDst(queue) {
+			if d == obj {
+				f.Delay += queue.GetLength()
+				break
+			}
+		}
+	}
+	return f
+}
+
+// Write standard report in GPSS World format
+func (r *StandardReport) Write(w io.Writer) {
+	width := 20
+	for _, b := range r.Blocks {
+		if len(b.Name) > width {
+			width = len(b.Name)
+		}
+	}
+	fmt.Fprintf(w, "\n              GPSS World Simulation Report - %s\n\n\n", r.Name)
+	fmt.Fprintf(w, "%*s %18s %7s %11s\n", width, "START TIME", "END TIME", "BLOCKS",
+		"FACILITIES")
+	fmt.Fprintf(w, "%*.3f %18.3f %7d %11d\n\n\n", width, float64(r.StartTime),
+		float64(r.EndTime), len(r.Blocks), len(r.Facilities))
+
+	fmt.Fprintf(w, " %-*s %5s  %-12s %12s %14s %9s\n", width, "LABEL", "LOC", "BLOCK TYPE",
+		"ENTRY COUNT", "CURRENT COUNT", "REFUSALS")
+	for _, b := range r.Blocks {
+		fmt.Fprintf(w, " %-*s %5d  %-12s %12d %14d %9d\n", width, b.Name, b.Loc, b.Type,
+			b.EntryCount, b.CurrentCount, b.Refusals)
+	}
+
+	if len(r.Facilities) > 0 {
+		fmt.Fprintf(w, "\n\n %-*s %8s %7s %10s %6s %6s %5s %6s %6s\n", width, "FACILITY",
+			"ENTRIES", "UTIL.", "AVE. TIME", "AVAIL.", "OWNER", "PEND", "INTER", "DELAY")
+		for _, f := range r.Facilities {
+			fmt.Fprintf(w, " %-*s %8d %7.3f %10.3f %6d %6d %5d %6d %6d\n", width, f.Name,
+				f.Entries, f.Util, f.AveTime, f.Avail, f.Owner, f.Pend, f.Inter, f.Delay)
+		}
+	}
+
+	if len(r.Queues) > 0 {
+		fmt.Fprintf(w, "\n\n %-*s %6s %6s %6s %9s %10s %10s %10s\n", width, "QUEUE",
+			"MAX", "CONT.", "ENTRY", "ENTRY(0)", "AVE.CONT.", "AVE.TIME", "AVE.(-0)")
+		for _, q := range r.Queues {
+			fmt.Fprintf(w, " %-*s %6d %6d %6d %9d %10.3f %10.3f %10.3f\n", width, q.Name,
+				q.Max, q.Cont, q.Entry, q.Entry0, q.AveCont, q.AveTime, q.AveTimeNonZero)
+		}
+	}
+
+	if len(r.Savevalues) > 0 {
+		fmt.Fprintf(w, "\n\n %-*s %14s\n", width, "SAVEVALUE", "VALUE")
+		for _, s := range r.Savevalues {
+			fmt.Fprintf(w, " %-*s %14.3f\n", width, s.Name, s.Value)
+		}
+	}
+
+	if len(r.Quantiles) > 0 {
+		fmt.Fprintf(w, "\n\n %-*s %-13s %8s %10s %10s %10s %10s %10s\n", width, "QUANTILES", "KIND",
+			"COUNT", "P50", "P90", "P95", "P99", "MAX")
+		for _, q := range r.Quantiles {
+			fmt.Fprintf(w, " %-*s %-13s %8d %10.3f %10.3f %10.3f %10.3f %10.3f\n", width, q.Name,
+				q.Kind, q.Count, q.P50, q.P90, q.P95, q.P99, q.Max)
+		}
+	}
+
+	for _, t := range r.Tables {
+		fmt.Fprintf(w, "\n\n %-*s %10s %10s %27s %10s %7s %7s\n", width, "TABLE", "MEAN",
+			"STD.DEV.", "RANGE", "FREQUENCY", "%", "CUM.%")
+		fmt.Fprintf(w, " %-*s %10.3f %10.3f\n", width, t.Name, t.Mean, t.StdDev)
+		for _, c := range t.Classes {
+			from, to := "-", "_"
+			if c.From != nil {
+				from = fmt.Sprintf("%.3f", *c.From)
+			}
+			if c.To != nil {
+				to = fmt.Sprintf("%.3f", *c.To)
+			}
+			fmt.Fprintf(w, " %-*s %10s %10s %12s - %12s %10d %7.2f %7.2f\n", width, "", "", "",
+				from, to, c.Frequency, c.Percent, c.Cumulative)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// Print standard report about work of pipeline in GPSS World format
+func (p *Pipeline) PrintStandardReport() {
+	p.GetStandardReport().Write(os.Stdout)
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPipeline_GetStandardReport(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 16, 4)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	pipe.SetSimTime(480)
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+
+	r := pipe.GetStandardReport()
+	if len(r.Blocks) != 4 || len(r.Facilities) != 1 || len(r.Queues) != 1 {
+		t.Fatal("Tables, expected 4 blocks, 1 facility, 1 queue, got", r)
+	}
+	types := []string{"GENERATE", "QUEUE", "FACILITY", "TERMINATE"}
+	for i, b := range r.Blocks {
+		if b.Type != types[i] || b.Loc != i+1 {
+			t.Error("Block", b.Name, "expected", types[i], i+1, "got", b.Type, b.Loc)
+		}
+	}
+	if r.Blocks[1].EntryCount != r.Queues[0].Entry {
+		t.Error("Entry count of queue, expected", r.Queues[0].Entry, "got", r.Blocks[1].EntryCount)
+	}
+	if u := r.Facilities[0].Util; u <= 0 || u > 1 {
+		t.Error("Utilization, expected in (0, 1], got", u)
+	}
+
+	var buf bytes.Buffer
+	r.Write(&buf)
+	for _, s := range []string{"ENTRY COUNT", "REFUSALS", "AVE.(-0)", "Master"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Error("Report, expected", s, "got", buf.String())
+		}
+	}
+	// Columns without data aren't written
+	for _, s := range []string{"RETRY", "STORAGES"} {
+		if strings.Contains(buf.String(), s) {
+			t.Error("Report, unexpected", s, "got", buf.String())
+		}
+	}
+	master := r.Blocks[2]
+	if master.Name != "Master" || master.Refusals == 0 {
+		t.Fatal("Refusals of Master, expected more than", 0, "got", master)
+	}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 6 && fields[0] == "Master" && fields[5] != strconv.Itoa(master.Refusals) {
+			t.Error("Refusals in block line, expected", master.Refusals, "got", line)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// Function for getting of sampled value from pipeline
+type ProbeFunc func(p *Pipeline) float64
+
+// Named source of sampled values
+type Probe struct {
+	Name  string    // Name of series
+	Probe ProbeFunc // Function for getting of value
+}
+
+// Values of all probes at model time
+type Sample struct {
+	ModelTime int
+	Values    []float64 // Values in the same order as probes
+}
+
+// Sampler records values of probes at fixed interval of model time or on
+// every change into series in memory
+type Sampler struct {
+	BaseObserver
+	pipe     *Pipeline  // Pipeline
+	probes   []Probe    // Sources of values
+	interval int        // Interval of sampling, 0 - sampling on every change
+	samples  []Sample   // Recorded samples
+	mu       sync.Mutex // Samples can be read while simulation is running
+}
+
+// Creates new Sampler.
+// pipe - pipeline for probes; interval - interval of model time between
+// samples, 0 - sample is recorded only if some value is changed;
+// probes - sources of values
+func NewSampler(pipe *Pipeline, interval int, probes ...Probe) *Sampler {
+	return &Sampler{pipe: pipe, interval: interval, probes: probes}
+}
+
+// Enable sampling of values of probes, see NewSampler
+func (p *Pipeline) EnableSampling(interval int, probes ...Probe) *Sampler {
+	s := NewSampler(p, interval, probes...)
+	p.AddObserver(s)
+	return s
+}
+
+// Probe of length of Queue
+func QueueLength(name string) Probe {
+	return Probe{Name: name, Probe: func(p *Pipeline) float64 {
+		if q, ok := p.GetObjByName(name).(IQueue); ok {
+			return float64(q.GetLength())
+		}
+		return 0
+	}}
+}
+
+// Probe of busy state of Facility or Bifacility, 1 - busy, 0 - empty
+func FacilityBusy(name string) Probe {
+	return Probe{Name: name, Probe: func(p *Pipeline) float64 {
+		if f, ok := p.GetObjByName(name).(IFacility); ok && !f.IsEmpty() {
+			return 1
+		}
+		return 0
+	}}
+}
+
+// Probe of value of Count, name is name of any part of Count
+func CountValue(name string) Probe {
+	return Probe{Name: name, Probe: func(p *Pipeline) float64 {
+		if c, ok := p.GetObjByName(name).(*Count); ok {
+			return float64(c.GetValue())
+		}
+		return 0
+	}}
+}
+
+// Probe of current count of transacts in object
+func CurrentCount(name string) Probe {
+	return Probe{Name: name, Probe: func(p *Pipeline) float64 {
+		return float64(p.GetBlockCounters(name).Current)
+	}}
+}
+
+func (s *Sampler) OnTick(modelTime int) {
+	if s.interval > 0 && modelTime%s.interval != 0 {
+		return
+	}
+	values := make([]float64, len(s.probes))
+	for i, probe := range s.probes {
+		values[i] = probe.Probe(s.pipe)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.interval == 0 && len(s.samples) > 0 && equalValues(s.samples[len(s.samples)-1].Values, values) {
+		return
+	}
+	s.samples = append(s.samples, Sample{ModelTime: modelTime, Values: values})
+}
+
+func equalValues(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Get names of series in order of probes
+func (s *Sampler) GetNames() []string {
+	names := make([]string, 0, len(s.probes))
+	for _, probe := range s.probes {
+		names = append(names, probe.Name)
+	}
+	return names
+}
+
+// Get copy of recorded samples
+func (s *Sampler) GetSamples() []Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	samples := make([]Sample, len(s.samples))
+	copy(samples, s.samples)
+	return samples
+}
+
+// Get series of probe by name, returns model times and values
+func (s *Sampler) GetSeries(name string) ([]int, []float64) {
+	index := -1
+	for i, probe := range s.probes {
+		if probe.Name == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	times := make([]int, 0, len(s.samples))
+	values := make([]float64, 0, len(s.samples))
+	for _, sample := range s.samples {
+		times = append(times, sample.ModelTime)
+		values = append(values, sample.Values[index])
+	}
+	return times, values
+}
+
+// Write samples in CSV format, first column is model time, next columns are
+// values of probes
+func (s *Sampler) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"model_time"}, s.GetNames()...)); err != nil {
+		return err
+	}
+	for _, sample := range s.GetSamples() {
+		record := make([]string, 0, len(sample.Values)+1)
+		record = append(record, strconv.Itoa(sample.ModelTime))
+		for _, v := range sample.Values {
+			record = append(record, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSampler(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 5, 0, 0, 0, nil)
+	inc, dec := NewCount("In shop", 1, -1)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 7, 0)
+	h := NewHole("Out")
+	pipe.Append(g, inc)
+	pipe.Append(inc, q)
+	pipe.Append(q, f)
+	pipe.Append(f, dec)
+	pipe.Append(dec, h)
+	pipe.Append(h)
+	interval := pipe.EnableSampling(10, QueueLength("Chairs"), FacilityBusy("Master"),
+		CountValue("In shop_INC"))
+	changes := pipe.EnableSampling(0, QueueLength("Chairs"))
+	pipe.SetSimTime(100)
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+
+	samples := interval.GetSamples()
+	if len(samples) != 10 {
+		t.Fatal("Samples, expected", 10, "got", len(samples))
+	}
+	last := samples[len(samples)-1]
+	if last.ModelTime != 90 {
+		t.Error("Last sample, expected at 90, got", last)
+	}
+	if last.Values[1] != 1 {
+		t.Error("Master is busy, expected", 1, "got", last.Values[1])
+	}
+	// Runs are repeated exactly, so samples are known
+	expected := [][]float64{{0, 0, 0}, {1, 1, 2}, {2, 0, 2}, {2, 1, 3}, {3, 1, 4},
+		{4, 1, 5}, {5, 0, 5}, {5, 1, 6}, {6, 1, 7}, {7, 1, 8}}
+	for i, s := range samples {
+		if s.ModelTime != i*10 || !reflect.DeepEqual(s.Values, expected[i]) {
+			t.Error("Sample", i, "expected at", i*10, "values", expected[i], "got", s)
+		}
+	}
+	times, values := changes.GetSeries("Chairs")
+	for i := 1; i < len(values); i++ {
+		if values[i] == values[i-1] {
+			t.Error("Sample on change at", times[i], "has same value as previous", values[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := interval.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "model_time,Chairs,Master,In shop_INC" || len(lines) != 11 {
+		t.Error("CSV, expected header and 10 lines, got", buf.String())
+	}
+}
@@ -25,7 +25,7 @@ type Split struct {
 func Splitting(obj *Split, transact ITransaction) {
 	cntsplit := obj.Cntsplit
 	if obj.Modificator > 0 {
-		cntsplit += GetRandom(-obj.Modificator, obj.Modificator)
+		cntsplit += obj.GetRandom(-obj.Modificator, obj.Modificator)
 	}
 
 	if cntsplit <= 0 {
@@ -36,6 +36,12 @@ func Splitting(obj *Split, transact ITransaction) {
 	}
 
 	obj.sum_split += float64(cntsplit)
+	var parts []ITransaction
+	defer func() {
+		for _, o := range getObservers(obj.GetPipeline()) {
+			o.OnSplit(obj, transact, parts)
+		}
+	}()
 	if cntsplit == len(obj.GetDst()) {
 		// Default case, cntsplit equal to length of GetDst()
 		for i, v := range obj.GetDst() {
@@ -43,7 +49,8 @@ func Splitting(obj *Split, transact ITransaction) {
 			parent_id := tr.GetId()
 			tr.SetID(obj.GetPipeline().GetIDNewTransaction())
 			tr.SetParts(i+1, cntsplit, parent_id)
-			v.AppendTransact(tr) // Take in mind that after Split must be only Queues
+			parts = append(parts, tr)
+			obj.SendPart(v, tr) // Take in mind that after Split must be only Queues
 		}
 	} else {
 		// Another case, cntsplit can be smaller than length of GetDst()
@@ -52,12 +59,13 @@ func Splitting(obj *Split, transact ITransaction) {
 		part_id := 1
 		for {
 			for _, v := range obj.GetDst() {
-				if GetRandomBool() && !dsts[part_id-1] {
+				if obj.GetRandomBool() && !dsts[part_id-1] {
 					tr := transact.Copy()
 					parent_id := tr.GetId()
 					tr.SetID(obj.GetPipeline().GetIDNewTransaction())
 					tr.SetParts(part_id, cntsplit, parent_id)
-					v.AppendTransact(tr)
+					parts = append(parts, tr)
+					obj.SendPart(v, tr)
 					dsts[part_id-1] = true
 					part_id++
 					if part_id > cntsplit {
@@ -85,8 +93,18 @@ func NewSplit(name string, cntsplit, modificator int, hndl HandleSplittingFunc)
 	return obj
 }
 
+// Send part of splitted transact to destination, custom splitting functions
+// must send parts by SendPart. Part refused by destination is lost.
+func (obj *Split) SendPart(dst IBaseObj, part ITransaction) bool {
+	addCounters(obj, 0, 1)
+	if !SendTransact(obj, dst, part) {
+		addCounters(obj, 0, -1)
+		return false
+	}
+	return true
+}
+
 func (obj *Split) HandleTransact(transact ITransaction) {
-	transact.PrintInfo()
 	obj.HandleSplitting(obj, transact)
 }
 
@@ -96,10 +114,11 @@ func (obj *Split) HandleTransacts(wg *sync.WaitGroup) {
 }
 
 func (obj *Split) AppendTransact(transact ITransaction) bool {
-	obj.GetLogger().GetTrace().Println("Append transact ", transact.GetId(), " to Split")
 	transact.SetHolderName(obj.name)
 	obj.sum_transact++
 	obj.HandleTransact(transact)
+	// Transact is replaced by parts
+	addCounters(obj, 0, -1)
 	return true
 }
 
@@ -108,3 +127,21 @@ func (obj *Split) PrintReport() {
 	fmt.Printf("Average split %.2f\n", obj.sum_split/obj.sum_transact)
 	fmt.Println()
 }
+
+type splitState struct {
+	SumSplit    float64
+	SumTransact float64
+}
+
+func (obj *Split) SaveState() ([]byte, error) {
+	return encodeState(splitState{SumSplit: obj.sum_split, SumTransact: obj.sum_transact})
+}
+
+func (obj *Split) LoadState(data []byte) error {
+	state := splitState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	obj.sum_split, obj.sum_transact = state.SumSplit, state.SumTransact
+	return nil
+}
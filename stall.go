@@ -0,0 +1,204 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Function for handling of detected stall
+type HandleStallFunc func(p *Pipeline, report *StallReport)
+
+// Waiting transact in stall report
+type StallEntry struct {
+	TransactID int      // ID of waiting transact
+	HolderName string   // Object where transact is
+	Targets    []string // Objects which transact tries to enter
+	Owners     []string // Who holds targets, in the same order as targets
+}
+
+// Report about stalled transacts: they are waiting and have not moved for span
+// of model time, other transacts can still move
+type StallReport struct {
+	ModelTime int          // Model time of detection
+	Since     int          // Model time of last movement of stalled transacts
+	Entries   []StallEntry // Stalled transacts
+}
+
+func (r *StallReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Stall detected at model time %d, transacts have not moved since %d\n",
+		r.ModelTime, r.Since)
+	for _, e := range r.Entries {
+		fmt.Fprintf(&b, "Transact %d in %q", e.TransactID, e.HolderName)
+		for i, target := range e.Targets {
+			if i == 0 {
+				b.WriteString(" tries to enter ")
+			} else {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%q", target)
+			if e.Owners[i] != "" {
+				fmt.Fprintf(&b, " (%s)", e.Owners[i])
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+type stallDetector struct {
+	span    int                        // Span of model time without movement, 0 - detection disabled
+	handle  HandleStallFunc            // Function for handling of detected stall
+	waiting map[ITransaction]waitState // Waiting transacts
+	report  *StallReport               // Last detected stall, nil if simulation is not stalled
+}
+
+// Holder of waiting transact and moment of start of waiting
+type waitState struct {
+	holderName string
+	since      int
+}
+
+// Default function for handling of detected stall, prints report to log
+func PrintStall(p *Pipeline, report *StallReport) {
+	p.GetLogger().GetWarning().Print(report)
+}
+
+// Enable detection of stall, when some waiting transacts have not moved for
+// span of model time, while objects they try to enter are held by transacts
+// which are waiting too. hndl is called once for each stall,
+// if hndl is nil, report is printed to log. Span 0 disables detection.
+func (p *Pipeline) SetStallDetection(span int, hndl HandleStallFunc) {
+	p.stall.span = span
+	p.stall.handle = hndl
+	if hndl == nil {
+		p.stall.handle = PrintStall
+	}
+}
+
+// Get report about last detected stall, nil if simulation is not stalled
+func (p *Pipeline) GetStallReport() *StallReport {
+	return p.stall.report
+}
+
+// Check movement of waiting transacts after step of simulation
+func (p *Pipeline) detectStall() {
+	if p.stall.span <= 0 {
+		return
+	}
+	transacts := p.getLiveTransacts()
+	waiting := make(map[ITransaction]waitState)
+	for _, t := range transacts {
+		// Transact with ticks is in service, it isn't waiting
+		if t.GetTicks() > 0 {
+			continue
+		}
+		state, ok := p.stall.waiting[t]
+		if !ok || state.holderName != t.GetHolderName() {
+			state = waitState{holderName: t.GetHolderName(), since: p.modelTime}
+		}
+		waiting[t] = state
+	}
+	p.stall.waiting = waiting
+	var stalled []ITransaction
+	for _, t := range transacts {
+		if p.isStalled(t) {
+			stalled = append(stalled, t)
+		}
+	}
+	if len(stalled) == 0 {
+		p.stall.report = nil
+		return
+	}
+	if p.stall.report != nil {
+		return
+	}
+	p.stall.report = p.newStallReport(stalled)
+	p.stall.handle(p, p.stall.report)
+}
+
+// Is transact waiting in the same holder for span of model time?
+func (p *Pipeline) isWaitingLong(t ITransaction) bool {
+	state, ok := p.stall.waiting[t]
+	return ok && p.modelTime-state.since >= p.stall.span
+}
+
+// Transact is stalled if it is waiting long and no object it tries to enter
+// is empty Facility or is held by transact which is in service or is waiting
+// not long
+func (p *Pipeline) isStalled(t ITransaction) bool {
+	if !p.isWaitingLong(t) {
+		return false
+	}
+	holder := p.objects[t.GetHolderName()]
+	if holder == nil {
+		return false
+	}
+	for _, d := range getAllDst(holder) {
+		switch d.(type) {
+		case *Facility, *InFacility:
+			if owner := p.getOwner(d); owner == nil || !p.isWaitingLong(owner) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (p *Pipeline) newStallReport(transacts []ITransaction) *StallReport {
+	report := &StallReport{ModelTime: p.modelTime, Since: p.modelTime}
+	for _, t := range transacts {
+		if since := p.stall.waiting[t].since; since < report.Since {
+			report.Since = since
+		}
+		entry := StallEntry{TransactID: t.GetId(), HolderName: t.GetHolderName()}
+		if holder := p.objects[t.GetHolderName()]; holder != nil {
+			for _, d := range getAllDst(holder) {
+				entry.Targets = append(entry.Targets, d.GetName())
+				entry.Owners = append(entry.Owners, p.getOwnerInfo(d))
+			}
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+	return report
+}
+
+// Get ID of transact which holds Facility or in part of Bifacility, 0 if
+// object is empty or can't be held
+func getHoldedID(obj IBaseObj) int {
+	switch o := obj.(type) {
+	case *Facility:
+		return o.HoldedTransactID
+	case *InFacility:
+		return o.HoldedTransactID
+	}
+	return 0
+}
+
+// Get transact which holds object, nil if object isn't held
+func (p *Pipeline) getOwner(obj IBaseObj) ITransaction {
+	if holdedID := getHoldedID(obj); holdedID > 0 {
+		return p.GetTransactByID(holdedID)
+	}
+	return nil
+}
+
+// Get information about transact which holds object
+func (p *Pipeline) getOwnerInfo(obj IBaseObj) string {
+	if o, ok := obj.(*OutFacility); ok {
+		return fmt.Sprintf("accepts only transact %d", o.inFacility.HoldedTransactID)
+	}
+	holdedID := getHoldedID(obj)
+	if holdedID <= 0 {
+		return ""
+	}
+	owner := p.GetTransactByID(holdedID)
+	if owner == nil {
+		return fmt.Sprintf("held by transact %d", holdedID)
+	}
+	return fmt.Sprintf("held by transact %d in %q", holdedID, owner.GetHolderName())
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"testing"
+)
+
+func TestPipeline_DetectStall(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 0, 0, 0, 2, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 2, 0)
+	closed := func(obj *Check, transact ITransaction) bool {
+		return false
+	}
+	check := NewCheck("Closed", closed, nil)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, check)
+	pipe.Append(check, h)
+	pipe.Append(h)
+	pipe.SetSimTime(100)
+
+	var stalls int
+	pipe.SetStallDetection(10, func(p *Pipeline, report *StallReport) {
+		stalls++
+	})
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+	if stalls != 1 {
+		t.Fatal("Stalls, expected", 1, "got", stalls)
+	}
+	report := pipe.GetStallReport()
+	if len(report.Entries) != 2 {
+		t.Fatal("Waiting transacts, expected", 2, "got", len(report.Entries))
+	}
+	waiting := report.Entries[1]
+	if waiting.HolderName != "Chairs" || len(waiting.Targets) != 1 || waiting.Targets[0] != "Master" {
+		t.Error("Waiting transact, expected in \"Chairs\" tries to enter \"Master\", got", waiting)
+	}
+	if waiting.Owners[0] != "held by transact 1 in \"Master\"" {
+		t.Error("Owner, expected transact 1, got", waiting.Owners[0])
+	}
+}
+
+func TestPipeline_DetectPartialStall(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 0, 0, 0, 2, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 2, 0)
+	closed := func(obj *Check, transact ITransaction) bool {
+		return false
+	}
+	check := NewCheck("Closed", closed, nil)
+	h := NewHole("Out")
+	// Flowing branch, its transacts are always in service
+	g2 := NewGenerator("Walkers", 3, 0, 0, 0, nil)
+	a := NewAdvance("Walk", 5, 0)
+	h2 := NewHole("Gone")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, check)
+	pipe.Append(check, h)
+	pipe.Append(h)
+	pipe.Append(g2, a)
+	pipe.Append(a, h2)
+	pipe.Append(h2)
+	pipe.SetSimTime(100)
+
+	var stalls int
+	pipe.SetStallDetection(10, func(p *Pipeline, report *StallReport) {
+		stalls++
+	})
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+	if h2.cnt_transact == 0 {
+		t.Error("Killed in flowing branch, expected more than", 0, "got", h2.cnt_transact)
+	}
+	if stalls != 1 {
+		t.Fatal("Stalls, expected", 1, "got", stalls)
+	}
+	report := pipe.GetStallReport()
+	if len(report.Entries) != 2 {
+		t.Fatal("Stalled transacts, expected", 2, "got", report.Entries)
+	}
+	for _, e := range report.Entries {
+		if e.HolderName != "Chairs" && e.HolderName != "Master" {
+			t.Error("Stalled transact, expected in blocked branch, got", e)
+		}
+	}
+}
+
+func TestPipeline_DetectStallLongService(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 5, 0, 0, 0, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 30, 0)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	pipe.SetSimTime(200)
+
+	var stalls int
+	pipe.SetStallDetection(10, func(p *Pipeline, report *StallReport) {
+		stalls++
+	})
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+	if stalls != 0 {
+		t.Error("Stalls of queue behind long service, expected", 0, "got", stalls)
+	}
+}
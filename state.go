@@ -0,0 +1,83 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+// Snapshot of state of pipeline
+type PipelineState struct {
+	Name      string       `json:"name"`
+	ModelTime int          `json:"model_time"`
+	SimTime   int          `json:"sim_time"`
+	Paused    bool         `json:"paused"`
+	Stopped   bool         `json:"stopped"`
+	Stall     string       `json:"stall,omitempty"` // Report about stall, empty if simulation isn't stalled
+	Blocks    []BlockState `json:"blocks"`
+}
+
+// Snapshot of state of object
+type BlockState struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Entries  int    `json:"entries"`
+	Current  int    `json:"current"`
+	Refusals int    `json:"refusals"`
+	Length   *int   `json:"length,omitempty"` // Length of Queue
+	Busy     *bool  `json:"busy,omitempty"`   // Busy state of Facility and Bifacility
+	Owner    int    `json:"owner,omitempty"`  // ID of transact in Facility and Bifacility
+	Value    *int   `json:"value,omitempty"`  // Value of Count
+}
+
+// Get snapshot of state of pipeline. It waits for end of current step, so it
+// must not be called from observers.
+func (p *Pipeline) GetState() *PipelineState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.getState()
+}
+
+// Get snapshot of state of pipeline, must be called between steps
+func (p *Pipeline) getState() *PipelineState {
+	state := &PipelineState{
+		Name:      p.name,
+		ModelTime: p.modelTime,
+		SimTime:   p.simTime,
+		Paused:    p.IsPaused(),
+		Stopped:   p.IsStopped(),
+	}
+	if p.stall.report != nil {
+		state.Stall = p.stall.report.String()
+	}
+	for _, o := range p.GetSortedObjects() {
+		state.Blocks = append(state.Blocks, p.getBlockState(o))
+	}
+	return state
+}
+
+// Get snapshot of state of object
+func (p *Pipeline) getBlockState(o IBaseObj) BlockState {
+	counters := p.GetBlockCounters(o.GetName())
+	b := BlockState{Name: o.GetName(), Type: getBlockType(o), Entries: counters.Entries,
+		Current: counters.Current, Refusals: counters.Refusals}
+	switch obj := o.(type) {
+	case *Queue:
+		length := obj.GetLength()
+		b.Length = &length
+	case *Facility:
+		busy := !obj.IsEmpty()
+		b.Busy = &busy
+		if busy {
+			b.Owner = obj.HoldedTransactID
+		}
+	case *InFacility:
+		busy := !obj.IsEmpty()
+		b.Busy = &busy
+		if busy {
+			b.Owner = obj.HoldedTransactID
+		}
+	case *Count:
+		value := obj.GetValue()
+		b.Value = &value
+	}
+	return b
+}
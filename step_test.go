@@ -0,0 +1,50 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPipeline_StepRepeatable(t *testing.T) {
+	run := func() []float64 {
+		p, in, _, h := newCheckpointPipeline()
+		p.SetSeed(7)
+		for !p.IsStopped() {
+			p.Step()
+		}
+		q := p.GetObjByName("Chairs").(*Queue)
+		return []float64{h.cnt_transact, h.sum_life, h.sum_advance, q.sum_Entries,
+			q.sum_zeroEntries, q.sum_timequeue, in.cnt_transact, float64(p.GetIDNewTransaction())}
+	}
+	expected := run()
+	for i := 0; i < 5; i++ {
+		if got := run(); !reflect.DeepEqual(got, expected) {
+			t.Fatal("Run with the same seed, expected", expected, "got", got)
+		}
+	}
+}
+
+func TestHole_KillOnEntering(t *testing.T) {
+	p := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 10, 0, 0, 0, nil)
+	a := NewAdvance("Work", 5, 0)
+	h := NewHole("Out")
+	p.Append(g, a)
+	p.Append(a, h)
+	p.Append(h)
+	p.SetSimTime(100)
+	for !p.IsStopped() {
+		p.Step()
+	}
+	if h.cnt_transact == 0 {
+		t.Fatal("Killed, expected not 0")
+	}
+	// Life of transact is time in advance, hole doesn't hold transacts
+	if h.sum_life != h.sum_advance {
+		t.Error("Sum of lifes, expected", h.sum_advance, "got", h.sum_life)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Table of frequency classes (GPSS TABLE and QTABLE). First class contains
+// values less or equal lower bound, each next class has width, last class
+// contains all values greater than upper bound of previous class.
+type Table struct {
+	name        string     // Name of table
+	LowerBound  float64    // Upper bound of first frequency class
+	Width       float64    // Width of frequency class
+	frequencies []int      // Observed frequencies of classes
+	entries     int        // Count of values
+	sum         float64    // Sum of values
+	sumSquares  float64    // Sum of squares of values
+	mu          sync.Mutex // Values are added from goroutines of objects
+}
+
+// Frequency class of table
+type TableClass struct {
+	Upper      float64 // Upper bound of class, +Inf for last class
+	Frequency  int     // Observed frequency
+	Percent    float64 // Percent of all values
+	Cumulative float64 // Cumulative percent
+}
+
+// Creates new Table.
+// name - name of table; lowerBound - upper bound of first frequency class;
+// width - width of frequency class, must be positive; count - count of
+// frequency classes, at least 2
+func NewTable(name string, lowerBound, width float64, count int) (*Table, error) {
+	if !(width > 0) || math.IsInf(width, 1) {
+		return nil, fmt.Errorf("table %q: width of frequency class must be positive, got %v", name, width)
+	}
+	if count < 2 {
+		return nil, fmt.Errorf("table %q: count of frequency classes must be at least 2, got %d", name, count)
+	}
+	return &Table{name: name, LowerBound: lowerBound, Width: width,
+		frequencies: make([]int, count)}, nil
+}
+
+// Get name of table
+func (t *Table) GetName() string {
+	return t.name
+}
+
+// Add value to table
+func (t *Table) Add(value float64) {
+	t.addCount(value, 1)
+}
+
+// Add value to table n times
+func (t *Table) addCount(value float64, n int) {
+	if n == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	class := 0
+	if value > t.LowerBound {
+		class = int(math.Ceil((value - t.LowerBound) / t.Width))
+		if class >= len(t.frequencies) {
+			class = len(t.frequencies) - 1
+		}
+	}
+	t.frequencies[class] += n
+	t.entries += n
+	t.sum += value * float64(n)
+	t.sumSquares += value * value * float64(n)
+}
+
+// Reset all frequencies and statistics of table
+func (t *Table) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.frequencies = make([]int, len(t.frequencies))
+	t.entries, t.sum, t.sumSquares = 0, 0, 0
+}
+
+// Get count of values
+func (t *Table) GetEntries() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.entries
+}
+
+// Get mean of values
+func (t *Table) GetMean() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries == 0 {
+		return 0
+	}
+	return t.sum / float64(t.entries)
+}
+
+// Get sample standard deviation of values
+func (t *Table) GetStdDev() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries < 2 {
+		return 0
+	}
+	n := float64(t.entries)
+	variance := (t.sumSquares - t.sum*t.sum/n) / (n - 1)
+	return math.Sqrt(math.Max(variance, 0))
+}
+
+// Get frequency classes of table
+func (t *Table) GetClasses() []TableClass {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	classes := make([]TableClass, len(t.frequencies))
+	cumulative := 0
+	for i, f := range t.frequencies {
+		cumulative += f
+		classes[i] = TableClass{Upper: t.LowerBound + float64(i)*t.Width, Frequency: f}
+		if t.entries > 0 {
+			classes[i].Percent = 100 * float64(f) / float64(t.entries)
+			classes[i].Cumulative = 100 * float64(cumulative) / float64(t.entries)
+		}
+	}
+	classes[len(classes)-1].Upper = math.Inf(1)
+	return classes
+}
+
+func (t *Table) PrintReport() {
+	fmt.Println("Table", t.name)
+	fmt.Printf("Entries %d\tMean %.3f\tStd. dev. %.3f\n", t.GetEntries(), t.GetMean(), t.GetStdDev())
+	fmt.Printf("%12s %12s %12s %12s %12s\n", "From", "To", "Frequency", "Percent", "Cum. percent")
+	from := math.Inf(-1)
+	for _, c := range t.GetClasses() {
+		if c.Frequency > 0 {
+			fmt.Printf("%12.3f %12.3f %12d %12.2f %12.2f\n", from, c.Upper, c.Frequency,
+				c.Percent, c.Cumulative)
+		}
+		from = c.Upper
+	}
+	fmt.Println()
+}
+
+type tableState struct {
+	Frequencies []int
+	Entries     int
+	Sum         float64
+	SumSquares  float64
+}
+
+func (t *Table) SaveState() ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return encodeState(tableState{Frequencies: t.frequencies, Entries: t.entries,
+		Sum: t.sum, SumSquares: t.sumSquares})
+}
+
+func (t *Table) LoadState(data []byte) error {
+	state := tableState{}
+	if err := decodeState(data, &state); err != nil {
+		return err
+	}
+	if len(state.Frequencies) != len(t.frequencies) {
+		return fmt.Errorf("table %q has %d classes, checkpoint has %d", t.name,
+			len(t.frequencies), len(state.Frequencies))
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.frequencies = state.Frequencies
+	t.entries, t.sum, t.sumSquares = state.Entries, state.Sum, state.SumSquares
+	return nil
+}
+
+// Append table to pipeline, tables are printed in report and saved in
+// checkpoints
+func (p *Pipeline) AppendTable(table *Table) {
+	if p.tables == nil {
+		p.tables = make(map[string]*Table)
+	}
+	if _, ok := p.tables[table.name]; !ok {
+		p.tableNames = append(p.tableNames, table.name)
+	}
+	p.tables[table.name] = table
+}
+
+// Get table from pipeline by name
+func (p *Pipeline) GetTable(name string) *Table {
+	return p.tables[name]
+}
+
+// Get all tables of pipeline in order of appending
+func (p *Pipeline) GetTables() []*Table {
+	tables := make([]*Table, 0, len(p.tableNames))
+	for _, name := range p.tableNames {
+		tables = append(tables, p.tables[name])
+	}
+	return tables
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTable_Add(t *testing.T) {
+	for _, width := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		if _, err := NewTable("table", 0, width, 4); err == nil {
+			t.Error("Table with width", width, "expected error, got nil")
+		}
+	}
+	for _, count := range []int{-1, 0, 1} {
+		if _, err := NewTable("table", 0, 10, count); err == nil {
+			t.Error("Table with count", count, "expected error, got nil")
+		}
+	}
+	table, err := NewTable("table", 0, 10, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []float64{-5, 0, 1, 10, 15, 20, 100} {
+		table.Add(v)
+	}
+	// Classes: <=0, (0, 10], (10, 20], >20
+	expected := []int{2, 2, 2, 1}
+	classes := table.GetClasses()
+	for i, c := range classes {
+		if c.Frequency != expected[i] {
+			t.Error("Frequency of class", i, "expected", expected[i], "got", c.Frequency)
+		}
+	}
+	if !math.IsInf(classes[3].Upper, 1) || classes[3].Cumulative != 100 {
+		t.Error("Last class, expected +Inf and 100%, got", classes[3])
+	}
+	if mean := table.GetMean(); mean != 20.142857142857142 {
+		t.Error("Mean, expected", 141.0/7, "got", mean)
+	}
+}
+
+func TestTabulate(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 5, 0, 0, 0, nil)
+	q := NewQueue("Chairs")
+	qtable, err := NewTable("Chairs", 0, 5, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.SetQTable(qtable)
+	f := NewFacility("Master", 7, 0)
+	life, err := NewTable("Life", 0, 5, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTabulate("Tabulate", nil, TabulateLife); err == nil {
+		t.Error("Tabulate without table, expected error, got nil")
+	}
+	if _, err := NewTabulate("Tabulate", life, nil); err == nil {
+		t.Error("Tabulate without handler, expected error, got nil")
+	}
+	tab, err := NewTabulate("Tabulate", life, TabulateLife)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, tab)
+	pipe.Append(tab, h)
+	pipe.Append(h)
+	pipe.AppendTable(qtable)
+	pipe.AppendTable(life)
+	pipe.SetSimTime(100)
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+
+	if pipe.GetTable("Life") != life {
+		t.Error("GetTable, expected", life, "got", pipe.GetTable("Life"))
+	}
+	if n, expected := life.GetEntries(), pipe.GetBlockCounters("Tabulate").Entries; n != expected {
+		t.Error("Entries of life table, expected", expected, "got", n)
+	}
+	if life.GetMean() < 7 {
+		t.Error("Mean of life, expected at least advance of facility 7, got", life.GetMean())
+	}
+	if n, expected := qtable.GetEntries(), int(q.sum_Entries)-q.GetLength(); n != expected {
+		t.Error("Entries of qtable, expected", expected, "got", n)
+	}
+}
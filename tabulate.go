@@ -0,0 +1,81 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"fmt"
+)
+
+// Function for getting of tabulated value from transact
+type HandleTabulateFunc func(obj *Tabulate, transact ITransaction) float64
+
+// Tabulate adds value of passing transact to table (GPSS TABULATE)
+type Tabulate struct {
+	BaseObj
+	Table          *Table             // Table for values
+	HandleTabulate HandleTabulateFunc // Function for getting of value
+}
+
+// Creates new Tabulate.
+// name - name of object; table - table for values; hndl - function for
+// getting of value from transact, for example TabulateLife. Table and hndl
+// must not be nil.
+func NewTabulate(name string, table *Table, hndl HandleTabulateFunc) (*Tabulate, error) {
+	if table == nil {
+		return nil, fmt.Errorf("tabulate %q: table is nil", name)
+	}
+	if hndl == nil {
+		return nil, fmt.Errorf("tabulate %q: handler is nil", name)
+	}
+	obj := &Tabulate{Table: table, HandleTabulate: hndl}
+	obj.name = name
+	return obj, nil
+}
+
+// Tabulate time of life of transact
+func TabulateLife(obj *Tabulate, transact ITransaction) float64 {
+	return float64(transact.GetLife())
+}
+
+// Tabulate time of transact in queues
+func TabulateQueueTime(obj *Tabulate, transact ITransaction) float64 {
+	return float64(transact.GetQueueTime())
+}
+
+// Tabulate numeric parameter of transact, transacts without parameter are
+// tabulated as 0
+func TabulateParameter(name string) HandleTabulateFunc {
+	return func(obj *Tabulate, transact ITransaction) float64 {
+		switch v := transact.GetParameterByName(name).(type) {
+		case int:
+			return float64(v)
+		case int64:
+			return float64(v)
+		case float32:
+			return float64(v)
+		case float64:
+			return v
+		}
+		return 0
+	}
+}
+
+func (obj *Tabulate) AppendTransact(transact ITransaction) bool {
+	return journalPass(obj.name, transact, func() bool {
+		value := obj.HandleTabulate(obj, transact)
+		for _, v := range obj.GetDst() {
+			if SendTransact(obj, v, transact) {
+				obj.Table.Add(value)
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (obj *Tabulate) PrintReport() {
+	fmt.Println("Tabulate", obj.name, "to table", obj.Table.GetName())
+	fmt.Println()
+}
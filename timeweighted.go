@@ -0,0 +1,150 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"math"
+	"sync"
+)
+
+// Time-weighted statistics of value which changes in model time, for example
+// content of queue or busy state of facility. Value is considered constant
+// between changes, so statistics are correct for any advance of model time.
+type TimeWeighted struct {
+	mu      sync.Mutex
+	start   int     // Model time of start of accumulation
+	last    int     // Model time of last change
+	current float64 // Current value
+	min     float64 // Min value
+	max     float64 // Max value
+	area    float64 // Area under value up to last change
+	area2   float64 // Area under square of value up to last change
+}
+
+// State of time-weighted statistics for saving in checkpoint
+type timeWeightedState struct {
+	Start   int
+	Last    int
+	Current float64
+	Min     float64
+	Max     float64
+	Area    float64
+	Area2   float64
+}
+
+// Creates new time-weighted statistics with value at model time
+func NewTimeWeighted(modelTime int, value float64) *TimeWeighted {
+	return &TimeWeighted{start: modelTime, last: modelTime, current: value, min: value, max: value}
+}
+
+// Accumulate area up to model time, must be called under lock
+func (tw *TimeWeighted) advance(modelTime int) {
+	if modelTime <= tw.last {
+		return
+	}
+	dt := float64(modelTime - tw.last)
+	tw.area += tw.current * dt
+	tw.area2 += tw.current * tw.current * dt
+	tw.last = modelTime
+}
+
+// Set value at model time, must be called under lock
+func (tw *TimeWeighted) set(modelTime int, value float64) {
+	tw.advance(modelTime)
+	tw.current = value
+	tw.min = math.Min(tw.min, value)
+	tw.max = math.Max(tw.max, value)
+}
+
+// Set value at model time
+func (tw *TimeWeighted) Update(modelTime int, value float64) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.set(modelTime, value)
+}
+
+// Change value by delta at model time
+func (tw *TimeWeighted) Add(modelTime int, delta float64) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.set(modelTime, tw.current+delta)
+}
+
+// Reset statistics at model time, current value is kept
+func (tw *TimeWeighted) Reset(modelTime int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.start, tw.last = modelTime, modelTime
+	tw.area, tw.area2 = 0, 0
+	tw.min, tw.max = tw.current, tw.current
+}
+
+// Get current value
+func (tw *TimeWeighted) GetCurrent() float64 {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.current
+}
+
+// Get min value
+func (tw *TimeWeighted) GetMin() float64 {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.min
+}
+
+// Get max value
+func (tw *TimeWeighted) GetMax() float64 {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.max
+}
+
+// Get area under value from start up to model time
+func (tw *TimeWeighted) GetArea(modelTime int) float64 {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.advance(modelTime)
+	return tw.area
+}
+
+// Get time-average of value from start up to model time
+func (tw *TimeWeighted) GetMean(modelTime int) float64 {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.advance(modelTime)
+	if tw.last <= tw.start {
+		return tw.current
+	}
+	return tw.area / float64(tw.last-tw.start)
+}
+
+// Get time-weighted variance of value from start up to model time
+func (tw *TimeWeighted) GetVariance(modelTime int) float64 {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.advance(modelTime)
+	if tw.last <= tw.start {
+		return 0
+	}
+	t := float64(tw.last - tw.start)
+	mean := tw.area / t
+	return math.Max(tw.area2/t-mean*mean, 0)
+}
+
+func (tw *TimeWeighted) getState() timeWeightedState {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return timeWeightedState{Start: tw.start, Last: tw.last, Current: tw.current,
+		Min: tw.min, Max: tw.max, Area: tw.area, Area2: tw.area2}
+}
+
+func (tw *TimeWeighted) setState(state timeWeightedState) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.start, tw.last, tw.current = state.Start, state.Last, state.Current
+	tw.min, tw.max = state.Min, state.Max
+	tw.area, tw.area2 = state.Area, state.Area2
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTimeWeighted(t *testing.T) {
+	tw := NewTimeWeighted(0, 0)
+	// 0 on [0, 2), 2 on [2, 5), 1 on [5, 10)
+	tw.Update(2, 2)
+	tw.Add(5, -1)
+	if area := tw.GetArea(10); area != 11 {
+		t.Error("Area, expected", 11, "got", area)
+	}
+	if mean := tw.GetMean(10); mean != 1.1 {
+		t.Error("Mean, expected", 1.1, "got", mean)
+	}
+	// (0*2 + 4*3 + 1*5) / 10 - 1.1^2
+	if variance := tw.GetVariance(10); math.Abs(variance-0.49) > 1e-9 {
+		t.Error("Variance, expected", 0.49, "got", variance)
+	}
+	if tw.GetMin() != 0 || tw.GetMax() != 2 || tw.GetCurrent() != 1 {
+		t.Error("Min, max, current, expected 0 2 1, got", tw.GetMin(), tw.GetMax(), tw.GetCurrent())
+	}
+
+	restored := NewTimeWeighted(0, 0)
+	restored.setState(tw.getState())
+	tw.Reset(10)
+	if mean := tw.GetMean(20); mean != 1 {
+		t.Error("Mean after reset, expected", 1, "got", mean)
+	}
+	if mean := restored.GetMean(20); mean != 1.05 {
+		t.Error("Mean of restored, expected", 1.05, "got", mean)
+	}
+}
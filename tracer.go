@@ -0,0 +1,148 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Kinds of trace events
+const (
+	EventCreated   = "created"
+	EventEnter     = "enter"
+	EventLeave     = "leave"
+	EventRefused   = "refused"
+	EventSplit     = "split"
+	EventAggregate = "aggregate"
+	EventKilled    = "killed"
+	EventTick      = "tick"
+	EventStop      = "stop"
+)
+
+// Filter of trace events. Zero value passes all events except ticks.
+type TraceFilter struct {
+	Objects       []string // Names of objects, empty - all objects
+	MinTransactID int      // Min ID of transact, 0 - no limit
+	MaxTransactID int      // Max ID of transact, 0 - no limit
+	From          int      // Start of window of model time
+	To            int      // End of window of model time, 0 - no limit
+	Ticks         bool     // Trace steps of simulation
+}
+
+// Tracer writes events of pipeline to structured log (log/slog) with model
+// time, object name, transact ID and kind of event as attributes. Events are
+// filtered before creating of attributes, so filtered events cost nothing.
+type Tracer struct {
+	logger  *slog.Logger
+	filter  TraceFilter
+	objects map[string]bool
+}
+
+// Creates new Tracer.
+// handler - handler of structured log; filter - filter of events
+func NewTracer(handler slog.Handler, filter TraceFilter) *Tracer {
+	t := &Tracer{logger: slog.New(handler), filter: filter}
+	if len(filter.Objects) > 0 {
+		t.objects = make(map[string]bool)
+		for _, name := range filter.Objects {
+			t.objects[name] = true
+		}
+	}
+	return t
+}
+
+// Enable tracing of events to trace log of pipeline, it writes to stdout in
+// verbose mode. Returns error if trace log is disabled, events can be traced
+// to own handler by NewTracer in this case.
+func (p *Pipeline) EnableTracing(filter TraceFilter) (*Tracer, error) {
+	if !p.logger.IsTraceEnabled() {
+		return nil, fmt.Errorf("trace log of pipeline %q is disabled, pipeline is not verbose", p.name)
+	}
+	t := NewTracer(p.logger.GetStructured().Handler(), filter)
+	p.AddObserver(t)
+	return t, nil
+}
+
+func (t *Tracer) isTimeEnabled(modelTime int) bool {
+	return modelTime >= t.filter.From && (t.filter.To == 0 || modelTime <= t.filter.To) &&
+		t.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+func (t *Tracer) isEnabled(obj IBaseObj, transact ITransaction) bool {
+	if t.objects != nil && !t.objects[obj.GetName()] {
+		return false
+	}
+	id := transact.GetId()
+	if id < t.filter.MinTransactID || (t.filter.MaxTransactID > 0 && id > t.filter.MaxTransactID) {
+		return false
+	}
+	return t.isTimeEnabled(obj.GetPipeline().GetModelTime())
+}
+
+func (t *Tracer) trace(event string, obj IBaseObj, transact ITransaction, attrs ...slog.Attr) {
+	if !t.isEnabled(obj, transact) {
+		return
+	}
+	attrs = append([]slog.Attr{
+		slog.Int("model_time", obj.GetPipeline().GetModelTime()),
+		slog.String("event", event),
+		slog.String("object", obj.GetName()),
+		slog.Int("transact", transact.GetId()),
+	}, attrs...)
+	t.logger.LogAttrs(context.Background(), slog.LevelDebug, event, attrs...)
+}
+
+func (t *Tracer) OnTransactionCreated(obj IBaseObj, transact ITransaction) {
+	t.trace(EventCreated, obj, transact)
+}
+
+func (t *Tracer) OnEnter(obj IBaseObj, transact ITransaction) {
+	t.trace(EventEnter, obj, transact)
+}
+
+func (t *Tracer) OnLeave(obj IBaseObj, transact ITransaction) {
+	t.trace(EventLeave, obj, transact)
+}
+
+func (t *Tracer) OnRefused(obj IBaseObj, transact ITransaction) {
+	t.trace(EventRefused, obj, transact)
+}
+
+func (t *Tracer) OnSplit(obj IBaseObj, parent ITransaction, parts []ITransaction) {
+	if !t.isEnabled(obj, parent) {
+		return
+	}
+	ids := make([]int, 0, len(parts))
+	for _, part := range parts {
+		ids = append(ids, part.GetId())
+	}
+	t.trace(EventSplit, obj, parent, slog.Any("parts", ids))
+}
+
+func (t *Tracer) OnAggregate(obj IBaseObj, transact ITransaction) {
+	t.trace(EventAggregate, obj, transact)
+}
+
+func (t *Tracer) OnKilled(obj IBaseObj, transact ITransaction) {
+	t.trace(EventKilled, obj, transact, slog.Int("life", transact.GetLife()))
+}
+
+func (t *Tracer) OnTick(modelTime int) {
+	if !t.filter.Ticks || !t.isTimeEnabled(modelTime) {
+		return
+	}
+	t.logger.LogAttrs(context.Background(), slog.LevelDebug, EventTick,
+		slog.Int("model_time", modelTime), slog.String("event", EventTick))
+}
+
+func (t *Tracer) OnStop(modelTime int) {
+	if !t.isTimeEnabled(modelTime) {
+		return
+	}
+	t.logger.LogAttrs(context.Background(), slog.LevelDebug, EventStop,
+		slog.Int("model_time", modelTime), slog.String("event", EventStop))
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+type traceRecord struct {
+	ModelTime int    `json:"model_time"`
+	Event     string `json:"event"`
+	Object    string `json:"object"`
+	Transact  int    `json:"transact"`
+}
+
+// Run barbershop with tracer and get traced events
+func runTracer(t *testing.T, filter TraceFilter) []traceRecord {
+	pipe := NewPipeline("Barbershop", false)
+	g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 16, 4)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	var buf bytes.Buffer
+	pipe.AddObserver(NewTracer(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), filter))
+	pipe.SetSeed(1)
+	pipe.SetSimTime(480)
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+	var records []traceRecord
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var r traceRecord
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestTracer_Filter(t *testing.T) {
+	all := runTracer(t, TraceFilter{})
+	if len(all) == 0 || all[len(all)-1].Event != EventStop {
+		t.Fatal("Events, expected events ended by stop, got", len(all))
+	}
+
+	byObject := runTracer(t, TraceFilter{Objects: []string{"Master"}})
+	for _, r := range byObject {
+		if r.Object != "Master" && r.Event != EventStop {
+			t.Error("Filter by object, expected Master, got", r)
+		}
+	}
+
+	byTransact := runTracer(t, TraceFilter{MinTransactID: 3, MaxTransactID: 5})
+	ids := make(map[int]bool)
+	for _, r := range byTransact {
+		if r.Event == EventStop {
+			continue
+		}
+		ids[r.Transact] = true
+		if r.Transact < 3 || r.Transact > 5 {
+			t.Error("Filter by transact, expected 3..5, got", r)
+		}
+	}
+	if len(ids) != 3 {
+		t.Error("Filter by transact, expected transacts 3, 4, 5, got", ids)
+	}
+
+	byTime := runTracer(t, TraceFilter{From: 100, To: 200, Ticks: true})
+	ticks := 0
+	for _, r := range byTime {
+		if r.ModelTime < 100 || r.ModelTime > 200 {
+			t.Error("Filter by time, expected 100..200, got", r)
+		}
+		if r.Event == EventTick {
+			ticks++
+		}
+	}
+	if ticks != 101 {
+		t.Error("Ticks, expected", 101, "got", ticks)
+	}
+}
+
+func TestPipeline_EnableTracing(t *testing.T) {
+	// Trace log of not verbose pipeline is disabled, events aren't formatted
+	pipe := NewPipeline("pipe", false)
+	if pipe.GetLogger().GetStructured().Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Trace log of not verbose pipeline, expected disabled")
+	}
+	if _, err := pipe.EnableTracing(TraceFilter{}); err == nil {
+		t.Error("Tracing to disabled log, expected error, got nil")
+	}
+	pipe.logger.SetTraceEnabled(true)
+	if !pipe.GetLogger().GetStructured().Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Enabled trace log, expected debug level enabled")
+	}
+	tracer, err := pipe.EnableTracing(TraceFilter{})
+	if err != nil || !tracer.isTimeEnabled(0) {
+		t.Error("Tracing to enabled log, expected enabled, got", err)
+	}
+}
@@ -5,6 +5,7 @@
 package gpss
 
 import (
+	"sort"
 	"sync"
 )
 
@@ -72,6 +73,34 @@ func (obj *TransactTable) GetItems() map[int]*TableItem {
 	return items //obj.mp
 }
 
+// Get items of table sorted by ID of transacts, for handling of transacts in
+// the same order in every run
+func getSortedItems(tb ITransactTable) []*TableItem {
+	items := make([]*TableItem, 0, tb.GetLen())
+	for _, v := range tb.GetItems() {
+		items = append(items, v)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].transact.GetId() < items[j].transact.GetId()
+	})
+	return items
+}
+
+// Get items of table for handling at current step sorted by ID of transacts.
+// Transacts which have entered holder at this step are handled from next
+// step, independently of order of objects.
+func getStepItems(tb ITransactTable) []*TableItem {
+	items := getSortedItems(tb)
+	n := 0
+	for _, v := range items {
+		if v.transact.GetTimeInHolder() > 0 {
+			items[n] = v
+			n++
+		}
+	}
+	return items[:n]
+}
+
 // Push transact to end table
 func (obj *TransactTable) Push(transact ITransaction) {
 	defer obj.mu.Unlock()
@@ -4,16 +4,22 @@
 
 package gpss
 
+import (
+	"context"
+	"log/slog"
+)
+
 type ITransaction interface {
 	SetID(int)                                  // Set transact ID
 	GetId() int                                 // Get transact ID
-	GetLife() int                               // Get transact time of life, rip - born
+	GetLife() int                               // Get transact time of life, rip - born, or model time - born for live transact
 	SetTiсks(interval int)                      // Set advance ticks
 	DecTiсks()                                  // Decrement ticks
 	GetTicks() int                              // Get current value of ticks
 	IsTheEnd() bool                             // Is ticks value equal zero?
 	SetHolderName(holderName string)            // Set holder of transact
 	GetHolderName() string                      // Get current holder of transact
+	GetTimeInHolder() int                       // Get time in current holder
 	InqQueueTime()                              // Increment time in queue
 	GetQueueTime() int                          // Get current value of time in queue
 	ResetQueueTime()                            // Reset time in queue
@@ -28,6 +34,7 @@ type ITransaction interface {
 	GetParameterByName(name string) interface{} // Get parameter of trunsuct by name
 	PrintInfo()                                 // Print info about transact
 	Copy() ITransaction                         // Create copy of transact
+	GetJournal() []JournalRecord                // Get history of transact
 }
 
 // Struct for splitting
@@ -40,15 +47,17 @@ type Parts struct {
 type Transaction struct {
 	id         int       // Transact ID
 	born       int       // Moment of borning
-	rip        int       // Kill moment
+	rip        int       // Kill moment, -1 for live transact
 	advance    int       // Full time in advice state
 	ticks      int       // Tiks for change state
 	holderName string    // Holder object name
+	holderTime int       // Moment of entering to holder
 	timequeue  int       // Time in queue at this moment
 	pipe       IPipeline // Pipeline
 	parts      Parts     /* For splitting. Default is "0/0". After splitting
 	may be "1/6" - first part of six parts or "5/6" - fifth part of six parts */
 	parameters map[string]interface{} // Parameters of transaction
+	journal    []JournalRecord        // History of transaction, if journal is enabled
 }
 
 func NewTransaction(id int, pipe IPipeline) ITransaction {
@@ -56,6 +65,7 @@ func NewTransaction(id int, pipe IPipeline) ITransaction {
 	t.id = id
 	t.pipe = pipe
 	t.born = pipe.GetModelTime()
+	t.rip = -1
 	t.parts = Parts{0, 0, 0}
 	t.parameters = make(map[string]interface{})
 	return t
@@ -71,11 +81,13 @@ func (t *Transaction) Copy() ITransaction {
 	copy_t.rip = t.rip
 	copy_t.timequeue = t.timequeue
 	copy_t.holderName = t.holderName
+	copy_t.holderTime = t.holderTime
 	copy_t.parts = t.parts
 	copy_t.parameters = make(map[string]interface{})
 	for key, value := range t.parameters {
 		copy_t.parameters[key] = value
 	}
+	copy_t.journal = append([]JournalRecord(nil), t.journal...)
 	return copy_t
 }
 
@@ -88,15 +100,29 @@ func (t *Transaction) GetId() int {
 }
 
 func (t *Transaction) GetLife() int {
+	if !t.IsKilled() && t.pipe != nil {
+		// Transact is alive, life lasts up to current model time
+		return t.pipe.GetModelTime() - t.born
+	}
 	return t.rip - t.born
 }
 
+// Print info about transact to structured trace log, nothing is formatted if
+// tracing is disabled
 func (t *Transaction) PrintInfo() {
-	trace := t.GetPipeline().GetLogger().GetTrace()
-	trace.Println("Transaction Id:\t", t.GetId(), "Borned:\t", t.born,
-		"Advance time:\t", t.advance, "Transaction life:\t",
-		t.GetPipeline().GetModelTime()-t.born, "Holder Name:\t", t.holderName,
-		"Tiks:\t\t", t.ticks, "Time in queue:\t", t.timequeue)
+	logger := t.GetPipeline().GetLogger().GetStructured()
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	logger.LogAttrs(context.Background(), slog.LevelDebug, "transact",
+		slog.Int("model_time", t.GetPipeline().GetModelTime()),
+		slog.Int("transact", t.id),
+		slog.Int("born", t.born),
+		slog.Int("advance", t.advance),
+		slog.Int("life", t.GetLife()),
+		slog.String("object", t.holderName),
+		slog.Int("ticks", t.ticks),
+		slog.Int("time_in_queue", t.timequeue))
 }
 
 // Set ticks and increases advance value to same value.
@@ -119,6 +145,14 @@ func (t *Transaction) IsTheEnd() bool {
 }
 
 func (t *Transaction) SetHolderName(holderName string) {
+	if t.holderName != holderName {
+		t.holderTime = t.GetPipeline().GetModelTime()
+		if isJournalEnabled(t.GetPipeline()) {
+			t.closeJournalRecord()
+			t.journal = append(t.journal, JournalRecord{Object: holderName,
+				Enter: t.holderTime, Leave: -1})
+		}
+	}
 	t.holderName = holderName
 }
 
@@ -126,6 +160,10 @@ func (t *Transaction) GetHolderName() string {
 	return t.holderName
 }
 
+func (t *Transaction) GetTimeInHolder() int {
+	return t.GetPipeline().GetModelTime() - t.holderTime
+}
+
 // Decremet ticks. If ticks is less than zero, set ticks value to zero.
 func (t *Transaction) DecTiсks() {
 	t.ticks--
@@ -136,10 +174,11 @@ func (t *Transaction) DecTiсks() {
 
 func (t *Transaction) Kill() {
 	t.rip = t.GetPipeline().GetModelTime()
+	t.closeJournalRecord()
 }
 
 func (t *Transaction) IsKilled() bool {
-	return bool(t.rip != 0)
+	return bool(t.rip >= 0)
 }
 
 func (t *Transaction) GetQueueTime() int {
@@ -183,3 +222,14 @@ func (t *Transaction) GetAllParameters() map[string]interface{} {
 func (t *Transaction) GetParameterByName(name string) interface{} {
 	return t.parameters[name]
 }
+
+func (t *Transaction) GetJournal() []JournalRecord {
+	return t.journal
+}
+
+// Set leave time of last record of journal, if transact is still in holder
+func (t *Transaction) closeJournalRecord() {
+	if n := len(t.journal); n > 0 && t.journal[n-1].Leave < 0 {
+		t.journal[n-1].Leave = t.GetPipeline().GetModelTime()
+	}
+}
@@ -16,3 +16,54 @@ func TestTransaction_GetId(t *testing.T) {
 		t.Error("Transact id, expected", id, "got", transact.GetId())
 	}
 }
+
+func TestTransaction_GetLife(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	transact := NewTransaction(1, pipe)
+	pipe.modelTime = 7
+	if transact.GetLife() != 7 {
+		t.Error("Life of live transact, expected", 7, "got", transact.GetLife())
+	}
+	transact.Kill()
+	pipe.modelTime = 10
+	if transact.GetLife() != 7 {
+		t.Error("Life of killed transact, expected", 7, "got", transact.GetLife())
+	}
+}
+
+func TestTransaction_GetJournal(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 0, 0, 0, 1, nil)
+	q := NewQueue("Chairs")
+	check := NewCheck("Check", nil, nil)
+	f := NewFacility("Master", 5, 0)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, check)
+	pipe.Append(check, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	pipe.EnableJournal(true)
+	pipe.SetSimTime(10)
+	for !pipe.IsStopped() {
+		pipe.Step()
+	}
+
+	transact := pipe.GetTransactByID(1)
+	expected := []JournalRecord{
+		{Object: "Clients", Enter: 0, Leave: 0},
+		{Object: "Chairs", Enter: 0, Leave: 0},
+		{Object: "Check", Enter: 0, Leave: 0},
+		{Object: "Master", Enter: 0, Leave: 5},
+		{Object: "Out", Enter: 5, Leave: 5},
+	}
+	journal := transact.GetJournal()
+	if len(journal) != len(expected) {
+		t.Fatal("Journal, expected", expected, "got", journal)
+	}
+	for i := range expected {
+		if journal[i] != expected[i] {
+			t.Error("Journal record", i, "expected", expected[i], "got", journal[i])
+		}
+	}
+}
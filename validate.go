@@ -0,0 +1,228 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of problems found by validation of pipeline
+const (
+	ProblemDuplicateName     = "duplicate name"      // Several objects with same name
+	ProblemDanglingDst       = "dangling dst"        // Dst is not appended to pipeline
+	ProblemEmptyDst          = "empty dst"           // Dst is required, but empty
+	ProblemMissingInFacility = "missing in facility" // Out part of Bifacility without in part
+	ProblemNilFalseObj       = "nil false object"    // Check without destination for false result
+	ProblemUnreachable       = "unreachable"         // Object is not reachable from any Generator
+	ProblemZeroTimeCycle     = "zero time cycle"     // Cycle without any time-consuming object
+)
+
+// Problem found by validation of pipeline
+type ValidationError struct {
+	Object  string // Name of object
+	Kind    string // Kind of problem
+	Message string // Description of problem
+	Warning bool   // Problem is a warning, simulation can be started
+}
+
+func (e *ValidationError) Error() string {
+	level := "error"
+	if e.Warning {
+		level = "warning"
+	}
+	return fmt.Sprintf("%s: %q: %s: %s", level, e.Object, e.Kind, e.Message)
+}
+
+// List of problems found by validation of pipeline
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, v := range e {
+		lines = append(lines, v.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Get only errors
+func (e ValidationErrors) Errors() ValidationErrors {
+	var errs ValidationErrors
+	for _, v := range e {
+		if !v.Warning {
+			errs = append(errs, v)
+		}
+	}
+	return errs
+}
+
+// Get only warnings
+func (e ValidationErrors) Warnings() ValidationErrors {
+	var warnings ValidationErrors
+	for _, v := range e {
+		if v.Warning {
+			warnings = append(warnings, v)
+		}
+	}
+	return warnings
+}
+
+// Get all destinations of object, includes destination of Check in case false
+// result of checking
+func getAllDst(obj IBaseObj) []IBaseObj {
+	dst := append(make([]IBaseObj, 0, len(obj.GetDst())+1), obj.GetDst()...)
+	if check, ok := obj.(*Check); ok && check.falseObj != nil {
+		dst = append(dst, check.falseObj)
+	}
+	return dst
+}
+
+// Is destination required for object? Unknown objects are skipped.
+func isDstRequired(obj IBaseObj) bool {
+	switch obj.(type) {
+	case *Generator, *Advance, *Queue, *Facility, *InFacility, *OutFacility,
+		*Split, *Aggregate, *Assign, *Count, *Check, *Tabulate:
+		return true
+	}
+	return false
+}
+
+// Is object sends transact to destination immediately, without spending of
+// model time? Unknown objects are considered time-consuming.
+func isImmediate(obj IBaseObj) bool {
+	switch obj.(type) {
+	case *Queue, *InFacility, *OutFacility, *Split, *Aggregate, *Check,
+		*Assign, *Count, *Tabulate, *Hole:
+		return true
+	}
+	return false
+}
+
+// Validate pipeline before start of simulation. Returns all found problems,
+// nil if pipeline is valid.
+func (p *Pipeline) Validate() ValidationErrors {
+	var problems ValidationErrors
+	add := func(obj, kind string, warning bool, format string, args ...interface{}) {
+		problems = append(problems, &ValidationError{Object: obj, Kind: kind,
+			Message: fmt.Sprintf(format, args...), Warning: warning})
+	}
+	objects := p.GetSortedObjects()
+
+	for _, name := range p.duplicates {
+		add(name, ProblemDuplicateName, false, "object is overwritten by another object with same name")
+	}
+
+	for _, o := range objects {
+		if isDstRequired(o) && len(o.GetDst()) == 0 {
+			add(o.GetName(), ProblemEmptyDst, false, "object has no destinations")
+		}
+		for _, d := range getAllDst(o) {
+			if p.objects[d.GetName()] != d {
+				add(o.GetName(), ProblemDanglingDst, false, "destination %q is not appended to pipeline", d.GetName())
+			}
+		}
+		if out, ok := o.(*OutFacility); ok && p.objects[out.inFacility.GetName()] != out.inFacility {
+			add(o.GetName(), ProblemMissingInFacility, false, "in part %q of bifacility is not appended to pipeline",
+				out.inFacility.GetName())
+		}
+	}
+
+	// Reachability from generators
+	reachable := make(map[IBaseObj]bool)
+	var visit func(obj IBaseObj)
+	visit = func(obj IBaseObj) {
+		if reachable[obj] {
+			return
+		}
+		reachable[obj] = true
+		for _, d := range getAllDst(obj) {
+			visit(d)
+		}
+	}
+	for _, o := range objects {
+		if _, ok := o.(*Generator); ok {
+			visit(o)
+		}
+	}
+	for _, o := range objects {
+		if !reachable[o] {
+			add(o.GetName(), ProblemUnreachable, true, "object is not reachable from any generator")
+			continue
+		}
+		if check, ok := o.(*Check); ok && check.falseObj == nil {
+			add(o.GetName(), ProblemNilFalseObj, true, "transact stays in previous object in case false result of checking")
+		}
+	}
+
+	for _, cycle := range p.findZeroTimeCycles(objects) {
+		names := make([]string, 0, len(cycle))
+		for _, o := range cycle {
+			names = append(names, fmt.Sprintf("%q", o.GetName()))
+		}
+		add(cycle[0].GetName(), ProblemZeroTimeCycle, false, "cycle without time-consuming object: %s",
+			strings.Join(names, ", "))
+	}
+	return problems
+}
+
+// Find cycles of objects which send transacts immediately (Tarjan's algorithm
+// of strongly connected components)
+func (p *Pipeline) findZeroTimeCycles(objects []IBaseObj) [][]IBaseObj {
+	var (
+		cycles  [][]IBaseObj
+		stack   []IBaseObj
+		index   = make(map[IBaseObj]int)
+		lowlink = make(map[IBaseObj]int)
+		onStack = make(map[IBaseObj]bool)
+		counter int
+	)
+	var strongConnect func(obj IBaseObj)
+	strongConnect = func(obj IBaseObj) {
+		index[obj] = counter
+		lowlink[obj] = counter
+		counter++
+		stack = append(stack, obj)
+		onStack[obj] = true
+		selfLoop := false
+		for _, d := range getAllDst(obj) {
+			if !isImmediate(d) {
+				continue
+			}
+			if d == obj {
+				selfLoop = true
+			}
+			if _, ok := index[d]; !ok {
+				strongConnect(d)
+				if lowlink[d] < lowlink[obj] {
+					lowlink[obj] = lowlink[d]
+				}
+			} else if onStack[d] && index[d] < lowlink[obj] {
+				lowlink[obj] = index[d]
+			}
+		}
+		if lowlink[obj] != index[obj] {
+			return
+		}
+		var component []IBaseObj
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append([]IBaseObj{top}, component...)
+			if top == obj {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			cycles = append(cycles, component)
+		}
+	}
+	for _, o := range objects {
+		if _, ok := index[o]; !ok && isImmediate(o) {
+			strongConnect(o)
+		}
+	}
+	return cycles
+}
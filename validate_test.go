@@ -0,0 +1,89 @@
+// Copyright 2019 Sergey Soldatov. All rights reserved.
+// This software may be modified and distributed under the terms
+// of the Apache license. See the LICENSE file for details.
+
+package gpss
+
+import (
+	"testing"
+)
+
+func hasProblem(problems ValidationErrors, object, kind string) bool {
+	for _, v := range problems {
+		if v.Object == object && v.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPipeline_Validate(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+	q := NewQueue("Chairs")
+	check := NewCheck("Check", nil, nil)
+	assign := NewAssign("Assign")
+	f := NewFacility("Master", 16, 4)
+	f2 := NewFacility("Master", 10, 4)
+	_, f_out := NewBifacility("Table")
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, check)
+	pipe.Append(check, assign)
+	pipe.Append(assign, q)
+	pipe.Append(f, h)
+	pipe.Append(f2, h)
+	pipe.Append(f_out)
+	pipe.Append(h)
+
+	problems := pipe.Validate()
+	expected := []struct{ object, kind string }{
+		{"Master", ProblemDuplicateName},
+		{"Table_OUT", ProblemEmptyDst},
+		{"Table_OUT", ProblemMissingInFacility},
+		{"Check", ProblemNilFalseObj},
+		{"Out", ProblemUnreachable},
+		{"Chairs", ProblemZeroTimeCycle},
+	}
+	for _, v := range expected {
+		if !hasProblem(problems, v.object, v.kind) {
+			t.Error("Problem", v.kind, "of", v.object, "expected, got", problems)
+		}
+	}
+	if len(problems.Errors()) == 0 {
+		t.Error("Errors, expected not empty")
+	}
+	if err := pipe.Start(10); err == nil {
+		t.Error("Start of invalid pipeline, expected error, got nil")
+	}
+}
+
+func TestPipeline_ValidateValid(t *testing.T) {
+	pipe := NewPipeline("pipe", false)
+	g := NewGenerator("Clients", 18, 6, 0, 0, nil)
+	q := NewQueue("Chairs")
+	f := NewFacility("Master", 16, 4)
+	h := NewHole("Out")
+	pipe.Append(g, q)
+	pipe.Append(q, f)
+	pipe.Append(f, h)
+	pipe.Append(h)
+	if problems := pipe.Validate(); problems != nil {
+		t.Error("Problems, expected nil, got", problems)
+	}
+	for _, simTime := range []int{0, -1} {
+		if err := pipe.Start(simTime); err == nil {
+			t.Error("Start with simulation time", simTime, "expected error, got nil")
+		}
+	}
+	if pipe.IsStopped() {
+		t.Fatal("Pipeline, expected not stopped after rejected start")
+	}
+	// Step with not positive simulation time stops pipeline without step
+	pipe.SetSimTime(0)
+	pipe.Step()
+	if !pipe.IsStopped() || pipe.GetModelTime() != 0 {
+		t.Error("Step with simulation time 0, expected stopped at 0, got",
+			pipe.IsStopped(), pipe.GetModelTime())
+	}
+}